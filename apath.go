@@ -1,7 +1,6 @@
 package apathy
 
 import (
-	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
@@ -14,6 +13,9 @@ type aPath struct {
 	aType APathType
 	mtime time.Time
 	size  int64
+	perm  fs.FileMode
+	sys   sysInfo
+	err   error
 }
 
 // NewAPath forms an absolute path and then performs an Lstat on it to capture the
@@ -28,46 +30,91 @@ func NewAPath(pieces ...APiece) (APath, error) {
 	return newAPathWith(absPath, lstat, err)
 }
 
+// MustNewAPath is like NewAPath but panics if the path cannot be constructed.
+func MustNewAPath(pieces ...APiece) APath {
+	return must(NewAPath(pieces...))
+}
+
 // NewAPathWith forms an absolute path based on the given path component(s), and uses the
 // provided fs.FileInfo to set the filesystem attributes of the path. This is useful when
 // you are walking a filesystem and have already obtained the fs.FileInfo for the path.
 // We only take a single piece because if you just went and did an lstat, you must have
 // assembled the path to pass to lstat.
+//
+// A relative path produces a *PathError wrapping ErrNotAbsolute.
 func NewAPathWith(path APiece, info fs.FileInfo, infoErr error) (APath, error) {
-	if !path.IsAbs() {
-		panic(fmt.Errorf("%w: expected absolute path: %s", ErrInternal, path))
-	}
 	return newAPathWith(path, info, infoErr)
 }
 
+// MustNewAPathWith is like NewAPathWith but panics if the path cannot be constructed.
+func MustNewAPathWith(path APiece, info fs.FileInfo, infoErr error) APath {
+	return must(NewAPathWith(path, info, infoErr))
+}
+
+// NewAPathTolerant is like NewAPath, except that an Lstat failure other than NotExist
+// does not fail construction. Instead, the APath is returned in the AInaccessible state
+// with the failure available from Err(), so that bulk operations can report errors
+// per path and keep going. Only a failure to resolve the pieces is returned as an error.
+func NewAPathTolerant(pieces ...APiece) (APath, error) {
+	absPath, err := resolvePieces(pieces...)
+	if err != nil {
+		return nil, err
+	}
+
+	lstat, err := Lstat(absPath.String())
+	p, err := newAPathTolerant("NewAPathTolerant", absPath, lstat, err)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// NewAPathWithTolerant is the NewAPathWith equivalent of NewAPathTolerant: an infoErr
+// other than NotExist produces an AInaccessible APath rather than an error.
+func NewAPathWithTolerant(path APiece, info fs.FileInfo, infoErr error) (APath, error) {
+	p, err := newAPathTolerant("NewAPathWithTolerant", path, info, infoErr)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
 func newAPathWith(absolutePath APiece, info fs.FileInfo, err error) (APath, error) {
+	p, err := newAPathTolerant("NewAPathWith", absolutePath, info, err)
+	if err != nil {
+		return nil, err
+	}
+	if p.err != nil {
+		// Strict construction treats anything other than NotExist as unrecoverable.
+		return nil, p.err
+	}
+	return p, nil
+}
+
+// newAPathTolerant is the common constructor; op names the public entry point for
+// any PathError.
+func newAPathTolerant(op string, absolutePath APiece, info fs.FileInfo, err error) (*aPath, error) {
 	// We've made them pass us the error so we can discriminate NotExists for the caller.
 	if !absolutePath.IsAbs() {
-		panic(fmt.Errorf("%w: non-absolute path leaked: %s", ErrInternal, absolutePath))
+		return nil, &PathError{Op: op, Piece: absolutePath, Err: ErrNotAbsolute}
 	}
 	if err != nil {
 		if !os.IsNotExist(err) {
-			// Anything else is unrecoverable.
-			return nil, err
+			// We can't tell what's there, but we can remember why.
+			return &aPath{APiece: absolutePath, aType: AInaccessible, err: err}, nil
 		}
 		// Fine, we'll represent a file that does not exist.
 		return &aPath{APiece: absolutePath}, nil
 	}
 	// It exists, let's look and see what it is
-	var aType APathType
-	var mode = info.Mode().Type()
-	switch {
-	case mode.IsRegular():
-		aType = ATypeFile
-	case mode.IsDir():
-		aType = ATypeDir
-	case mode&fs.ModeSymlink != 0:
-		aType = ATypeSymlink
-	default:
-		aType = ATypeUnknown
-	}
-
-	return &aPath{APiece: absolutePath, aType: aType, mtime: info.ModTime(), size: info.Size()}, nil
+	return &aPath{
+		APiece: absolutePath,
+		aType:  APathTypeOf(info),
+		mtime:  info.ModTime(),
+		size:   info.Size(),
+		perm:   info.Mode().Perm(),
+		sys:    sysInfoOf(info),
+	}, nil
 }
 
 func (p *aPath) Piece() APiece {
@@ -86,14 +133,44 @@ func (p *aPath) ModTime() time.Time {
 func (p *aPath) Size() int64 {
 	return p.size
 }
+
+// Perm returns the permission bits captured by the last Lstat.
+func (p *aPath) Perm() fs.FileMode {
+	return p.perm
+}
+
+// FileID returns the device/inode identity captured by the last Lstat, if the
+// platform provided one.
+func (p *aPath) FileID() (FileID, bool) {
+	return p.sys.id, p.sys.valid
+}
+
+// Nlink returns the hard-link count captured by the last Lstat, or 0 if the
+// platform did not provide one.
+func (p *aPath) Nlink() uint64 {
+	return p.sys.nlink
+}
+
+// Owner returns the uid and gid captured by the last Lstat, if the platform
+// provided them.
+func (p *aPath) Owner() (uid, gid int, ok bool) {
+	return p.sys.uid, p.sys.gid, p.sys.valid
+}
+
 func (p *aPath) IsAbs() bool {
 	return true
 }
 
-// Exists returns true if our last Lstat of the filesystem object did not produce a NotExist error.
+// Err returns the error that left this APath AInaccessible, or nil.
+func (p *aPath) Err() error {
+	return p.err
+}
+
+// Exists returns true if our last Lstat of the filesystem object found it. An AInaccessible
+// path is not considered to exist, since we couldn't confirm that it does.
 // Use Observe() to refresh.
 func (p *aPath) Exists() bool {
-	return p.aType != ANotExist
+	return p.aType != ANotExist && p.aType != AInaccessible
 }
 
 // IsSymlink returns true if the last LStat of the filesystem object found a symbolink link,
@@ -115,14 +192,22 @@ func (p *aPath) IsDir() bool {
 // resolvePieces will combine several pieces into an absolute path.
 func resolvePieces(pieces ...APiece) (APiece, error) {
 	if len(pieces) == 0 {
-		panic(fmt.Errorf("%w: resolvePieces requires at least one APiece", ErrMissingArgs))
+		return "", ErrNoPieces
 	}
-	fullPath := Join(pieces...).String()
-	fullPath, err := Abs(fullPath)
+	joined := Join(pieces...)
+	fullPath, err := Abs(joined.String())
 	if err != nil {
-		return "", fmt.Errorf("error resolving path: %w", err)
+		return "", &PathError{Op: "resolve", Piece: joined, Err: err}
 	}
 	// filepath on windows will introduce native separators
 	fullPath = filepath.ToSlash(fullPath)
 	return APiece(fullPath), nil
 }
+
+// must panics if err is not nil, otherwise returns the value.
+func must[T any](value T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
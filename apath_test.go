@@ -101,6 +101,11 @@ func Test_aPath_resolvePieces_error(t *testing.T) {
 	})()
 	_, err := resolvePieces(APiece("."))
 	assert.ErrorIs(t, err, myError)
+	var pathErr *PathError
+	if assert.ErrorAs(t, err, &pathErr) {
+		assert.Equal(t, "resolve", pathErr.Op)
+		assert.Equal(t, Dot, pathErr.Piece)
+	}
 }
 
 func Test_aPath_resolvePieces(t *testing.T) {
@@ -113,9 +118,9 @@ func Test_aPath_resolvePieces(t *testing.T) {
 	assert.NoError(t, err)
 
 	t.Run("err on no pieces", func(t *testing.T) {
-		assert.Panics(t, func() {
-			_, _ = resolvePieces()
-		})
+		_, err := resolvePieces()
+		assert.ErrorIs(t, err, ErrNoPieces)
+		assert.ErrorIs(t, err, ErrMissingArgs)
 	})
 	t.Run("Pwd on empty product", func(t *testing.T) {
 		p, err := resolvePieces(APiece(""))
@@ -150,13 +155,16 @@ func Test_aPath_resolvePieces(t *testing.T) {
 	})
 }
 
-func Test_newAPathWith_PanicRelativeArg(t *testing.T) {
+func Test_newAPathWith_RelativeArg(t *testing.T) {
 	t.Parallel()
-	wantErr := fmt.Errorf("%w: non-absolute path leaked: %s", ErrInternal, ".")
-	assert.PanicsWithError(t, fmt.Sprint(wantErr), func() {
-		_, err := newAPathWith(Dot, nil, nil)
-		assert.NoError(t, err)
-	})
+	p, err := newAPathWith(Dot, nil, nil)
+	assert.Nil(t, p)
+	assert.ErrorIs(t, err, ErrNotAbsolute)
+	var pathErr *PathError
+	if assert.ErrorAs(t, err, &pathErr) {
+		assert.Equal(t, Dot, pathErr.Piece)
+		assert.Equal(t, "NewAPathWith .: path is not absolute", pathErr.Error())
+	}
 }
 
 func Test_newAPathWith_HardError(t *testing.T) {
@@ -167,6 +175,19 @@ func Test_newAPathWith_HardError(t *testing.T) {
 	assert.Nil(t, p)
 }
 
+func Test_newAPathTolerant_HardError(t *testing.T) {
+	t.Parallel()
+	fakeErr := errors.New("no cookie for you")
+	p, err := newAPathTolerant("Test", "/jar", nil, fakeErr)
+	assert.NoError(t, err)
+	assert.NotNil(t, p)
+	assert.Equal(t, "/jar", p.String())
+	assert.Equal(t, AInaccessible, p.Type())
+	assert.ErrorIs(t, p.Err(), fakeErr)
+	assert.False(t, p.Exists())
+	assert.False(t, p.IsFile())
+}
+
 func Test_newAPathWith_NotExist(t *testing.T) {
 	t.Parallel()
 	p, err := newAPathWith("/", nil, os.ErrNotExist)
@@ -192,7 +213,8 @@ func Test_newAPathWith_Exists(t *testing.T) {
 		{"folder", "/foo", mockLstat(os.ModeDir), ATypeDir},
 		{"file", "/file", mockLstat(0), ATypeFile},
 		{"symlink", "/symlink", mockLstat(os.ModeSymlink), ATypeSymlink},
-		{"device", "/device", mockLstat(os.ModeDevice), ATypeUnknown},
+		{"device", "/device", mockLstat(os.ModeDevice), ATypeBlockDevice},
+		{"pipe", "/pipe", mockLstat(os.ModeNamedPipe), ATypeNamedPipe},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			// deliberately pass it a different path, it shouldn't be looking at the path.
@@ -206,10 +228,24 @@ func Test_newAPathWith_Exists(t *testing.T) {
 	}
 }
 
-func TestNewAPath_PanicsOnZeroPieces(t *testing.T) {
+func TestNewAPath_ZeroPieces(t *testing.T) {
 	t.Parallel()
-	assert.Panics(t, func() {
-		_, _ = NewAPath()
+	p, err := NewAPath()
+	assert.ErrorIs(t, err, ErrNoPieces)
+	assert.Nil(t, p)
+	p, err = NewAPathTolerant()
+	assert.ErrorIs(t, err, ErrNoPieces)
+	assert.Nil(t, p)
+}
+
+func TestMustNewAPath(t *testing.T) {
+	t.Parallel()
+	assert.PanicsWithError(t, ErrNoPieces.Error(), func() {
+		_ = MustNewAPath()
+	})
+	assert.NotPanics(t, func() {
+		p := MustNewAPath(NewAPiece(myExecutable))
+		assert.True(t, p.IsFile())
 	})
 }
 
@@ -240,6 +276,48 @@ func TestNewAPath_Errors(t *testing.T) {
 	assert.Nil(t, p)
 }
 
+func TestNewAPathTolerant(t *testing.T) {
+	// Can't be parallel because it modifies globals.
+	defer withSaved(&Abs, func(child string) (string, error) {
+		return "/x/" + child, nil
+	})()
+	var lstatErr = errors.New("no cookie for you")
+	defer withSaved(&Lstat, func(in string) (os.FileInfo, error) {
+		switch in {
+		case "/x/jar":
+			return nil, lstatErr
+		case "/x/missing":
+			return nil, os.ErrNotExist
+		default:
+			return mockFileInfo{mode: 0644, size: 3}, nil
+		}
+	})()
+
+	p, err := NewAPathTolerant("jar")
+	assert.NoError(t, err)
+	assert.Equal(t, AInaccessible, p.Type())
+	assert.ErrorIs(t, p.Err(), lstatErr)
+
+	p, err = NewAPathTolerant("missing")
+	assert.NoError(t, err)
+	assert.Equal(t, ANotExist, p.Type())
+	assert.NoError(t, p.Err())
+
+	p, err = NewAPathTolerant("cookie")
+	assert.NoError(t, err)
+	assert.Equal(t, ATypeFile, p.Type())
+	assert.NoError(t, p.Err())
+
+	// Failing to resolve is still an error.
+	var absErr = errors.New("lost in space")
+	defer withSaved(&Abs, func(string) (string, error) {
+		return "", absErr
+	})()
+	p, err = NewAPathTolerant("cookie")
+	assert.ErrorIs(t, err, absErr)
+	assert.Nil(t, p)
+}
+
 func TestNewAPath_Basic(t *testing.T) {
 	// I want to go the whole hog and test the real Abs method, and the only
 	// things we can reasonably rely on are the path to the executable and
@@ -275,11 +353,40 @@ func TestNewAPath_Basic(t *testing.T) {
 	}
 }
 
-func TestNewAPathWith_PanicOnRelative(t *testing.T) {
+func TestNewAPathWith_Relative(t *testing.T) {
+	t.Parallel()
+	p, err := NewAPathWith(Dot, nil, nil)
+	assert.ErrorIs(t, err, ErrNotAbsolute)
+	assert.Nil(t, p)
+}
+
+func TestMustNewAPathWith(t *testing.T) {
 	t.Parallel()
 	assert.Panics(t, func() {
-		_, _ = NewAPathWith(Dot, nil, nil)
+		_ = MustNewAPathWith(Dot, nil, nil)
 	})
+	p := MustNewAPathWith("/biscuit/gravy", nil, os.ErrNotExist)
+	assert.False(t, p.Exists())
+}
+
+func TestNewAPathWithTolerant(t *testing.T) {
+	t.Parallel()
+	p, err := NewAPathWithTolerant(Dot, nil, nil)
+	assert.ErrorIs(t, err, ErrNotAbsolute)
+	assert.Nil(t, p)
+	// The error names the function that was called.
+	var pathErr *PathError
+	if assert.ErrorAs(t, err, &pathErr) {
+		assert.Equal(t, "NewAPathWithTolerant", pathErr.Op)
+	}
+	_, err = NewAPathWith(Dot, nil, nil)
+	if assert.ErrorAs(t, err, &pathErr) {
+		assert.Equal(t, "NewAPathWith", pathErr.Op)
+	}
+	apath, err := NewAPathWithTolerant("/biscuit/gravy", nil, os.ErrPermission)
+	assert.NoError(t, err)
+	assert.Equal(t, AInaccessible, apath.Type())
+	assert.ErrorIs(t, apath.Err(), os.ErrPermission)
 }
 
 func TestNewAPathWith(t *testing.T) {
@@ -2,7 +2,7 @@ package apathy
 
 import (
 	"io/fs"
-	"os"
+	"strings"
 )
 
 // APathType tells us what we discovered about a directory item when we last Lstat()ed it,
@@ -10,48 +10,148 @@ import (
 type APathType uint32
 
 const (
-	ANotExist    APathType = iota // ANotExist indicates the file was not found when last Lstat()d.
-	ATypeFile                     // ATypeFile indicates the last Lstat() of the path found a regular file.
-	ATypeDir                      // ATypeDir indicates the last LStat() of the path found a regular directory.
-	ATypeSymlink                  // ATypeSymlink indicates the last LStat() of the path found a symbolic link.
-	ATypeUnknown
+	ANotExist        APathType = iota // ANotExist indicates the file was not found when last Lstat()d.
+	ATypeFile                         // ATypeFile indicates the last Lstat() of the path found a regular file.
+	ATypeDir                          // ATypeDir indicates the last LStat() of the path found a regular directory.
+	ATypeSymlink                      // ATypeSymlink indicates the last LStat() of the path found a symbolic link.
+	ATypeUnknown                      // ATypeUnknown indicates the last Lstat() found something we couldn't classify.
+	ATypeNamedPipe                    // ATypeNamedPipe indicates the last Lstat() found a FIFO.
+	ATypeSocket                       // ATypeSocket indicates the last Lstat() found a unix domain socket.
+	ATypeCharDevice                   // ATypeCharDevice indicates the last Lstat() found a character device.
+	ATypeBlockDevice                  // ATypeBlockDevice indicates the last Lstat() found a block device.
+	ATypeIrregular                    // ATypeIrregular indicates the last Lstat() found a non-regular file of unknown kind.
+	AInaccessible                     // AInaccessible indicates the last Lstat() failed for a reason other than NotExist.
+
+	numAPathTypes // keep last
 )
 
+var aPathTypeNames = [numAPathTypes]string{
+	ANotExist:        "NotExist",
+	ATypeFile:        "File",
+	ATypeDir:         "Dir",
+	ATypeSymlink:     "Symlink",
+	ATypeUnknown:     "Unknown",
+	ATypeNamedPipe:   "NamedPipe",
+	ATypeSocket:      "Socket",
+	ATypeCharDevice:  "CharDevice",
+	ATypeBlockDevice: "BlockDevice",
+	ATypeIrregular:   "Irregular",
+	AInaccessible:    "Inaccessible",
+}
+
 func (a APathType) String() string {
-	switch a {
-	case ANotExist:
-		return "NotExist"
-	case ATypeFile:
-		return "File"
-	case ATypeDir:
-		return "Dir"
-	case ATypeSymlink:
-		return "Symlink"
-	default:
-		return "Unknown"
+	if a < numAPathTypes {
+		return aPathTypeNames[a]
 	}
+	return "Unknown"
 }
 
-// fileInfoToAPathType converts a fs.FileInfo to an APathType by inspecting
-// the mode of the file. If the fs.FileInfo is nil, it returns ANotExist.
-// Note that APathType considers symlinks a distinct type separate from
-// files and directories, as this makes life easier on Windows in most cases.
-func fileInfoToAPathType(info fs.FileInfo, err error) (APathType, error) {
-	if err != nil {
-		if !os.IsNotExist(err) {
-			return ANotExist, err
-		}
-		return ANotExist, nil
-	}
-	mode := info.Mode()
-	switch {
+// APathTypeOfMode is the one place we map an fs.FileMode to an APathType. Only the
+// type bits of the mode are considered. Note that APathType considers symlinks a
+// distinct type separate from files and directories, as this makes life easier on
+// Windows in most cases.
+func APathTypeOfMode(mode fs.FileMode) APathType {
+	switch mode = mode.Type(); {
 	case mode.IsRegular():
-		return ATypeFile, nil
-	case mode.Type()&fs.ModeSymlink != 0:
-		return ATypeSymlink, nil
+		return ATypeFile
+	case mode&fs.ModeSymlink != 0:
+		return ATypeSymlink
 	case mode.IsDir():
-		return ATypeDir, nil
+		return ATypeDir
+	case mode&fs.ModeNamedPipe != 0:
+		return ATypeNamedPipe
+	case mode&fs.ModeSocket != 0:
+		return ATypeSocket
+	case mode&fs.ModeCharDevice != 0:
+		return ATypeCharDevice
+	case mode&fs.ModeDevice != 0:
+		return ATypeBlockDevice
+	case mode&fs.ModeIrregular != 0:
+		return ATypeIrregular
 	default:
-		return ATypeUnknown, nil
+		return ATypeUnknown
+	}
+}
+
+// APathTypeOf returns the APathType for an Lstat result, or ANotExist if info is nil.
+func APathTypeOf(info fs.FileInfo) APathType {
+	if info == nil {
+		return ANotExist
+	}
+	return APathTypeOfMode(info.Mode())
+}
+
+// APathTypeOfEntry returns the APathType for a directory entry, as obtained from
+// ReadDir or WalkDir, without calling Info() on it.
+func APathTypeOfEntry(entry fs.DirEntry) APathType {
+	if entry == nil {
+		return ANotExist
+	}
+	return APathTypeOfMode(entry.Type())
+}
+
+// APathTypeSet is a bitmask of APathTypes, allowing walkers and filters to select
+// which kinds of item they are interested in.
+type APathTypeSet uint32
+
+const (
+	// ATypesNone matches nothing.
+	ATypesNone APathTypeSet = 0
+	// ATypesAll matches every APathType, including ANotExist.
+	ATypesAll APathTypeSet = 1<<numAPathTypes - 1
+	// ATypesExisting matches every APathType except ANotExist and AInaccessible.
+	ATypesExisting = ATypesAll &^ (1<<ANotExist | 1<<AInaccessible)
+	// ATypesSpecial matches pipes, sockets, devices and irregular files.
+	ATypesSpecial APathTypeSet = 1<<ATypeNamedPipe | 1<<ATypeSocket | 1<<ATypeCharDevice |
+		1<<ATypeBlockDevice | 1<<ATypeIrregular
+)
+
+// NewAPathTypeSet returns a set containing the given types.
+func NewAPathTypeSet(types ...APathType) APathTypeSet {
+	return ATypesNone.With(types...)
+}
+
+// Set returns a set containing only this type.
+func (a APathType) Set() APathTypeSet {
+	return NewAPathTypeSet(a)
+}
+
+// With returns a copy of the set with the given types added.
+func (s APathTypeSet) With(types ...APathType) APathTypeSet {
+	for _, t := range types {
+		s |= 1 << t
+	}
+	return s
+}
+
+// Without returns a copy of the set with the given types removed.
+func (s APathTypeSet) Without(types ...APathType) APathTypeSet {
+	for _, t := range types {
+		s &^= 1 << t
+	}
+	return s
+}
+
+// Has returns true if the set includes the given type.
+func (s APathTypeSet) Has(t APathType) bool {
+	return t < numAPathTypes && s&(1<<t) != 0
+}
+
+// Matches returns true if the set includes the type of the given APath.
+func (s APathTypeSet) Matches(p APath) bool {
+	return s.Has(p.Type())
+}
+
+// String lists the members of the set, separated by '|'.
+func (s APathTypeSet) String() string {
+	var names []string
+	for t := ANotExist; t < numAPathTypes; t++ {
+		if s.Has(t) {
+			names = append(names, t.String())
+		}
+	}
+	if len(names) == 0 {
+		return "None"
 	}
+	return strings.Join(names, "|")
 }
@@ -1,9 +1,7 @@
 package apathy
 
 import (
-	"errors"
 	"io/fs"
-	"os"
 	"testing"
 	"time"
 
@@ -25,16 +23,13 @@ func (m mockFileInfo) ModTime() time.Time { return m.mtime }
 func (m mockFileInfo) IsDir() bool        { return m.mode.IsDir() }
 func (m mockFileInfo) Sys() interface{}   { return nil }
 
-func Test_fileInfoToAPathType(t *testing.T) {
+func TestAPathTypeOf(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
 		name     string
 		info     fs.FileInfo
-		err      error
 		wantType APathType
-		wantErr  bool
-		errIs    error // expected specific error
 	}{
 		{
 			name:     "regular file",
@@ -54,39 +49,37 @@ func Test_fileInfoToAPathType(t *testing.T) {
 		{
 			name:     "special file (device)",
 			info:     mockFileInfo{mode: fs.ModeDevice},
-			wantType: ATypeUnknown,
+			wantType: ATypeBlockDevice,
+		},
+		{
+			name:     "special file (char device)",
+			info:     mockFileInfo{mode: fs.ModeDevice | fs.ModeCharDevice},
+			wantType: ATypeCharDevice,
 		},
 		{
 			name:     "special file (named pipe)",
 			info:     mockFileInfo{mode: fs.ModeNamedPipe},
-			wantType: ATypeUnknown,
+			wantType: ATypeNamedPipe,
 		},
 		{
-			name:     "nil FileInfo",
-			info:     nil,
-			err:      os.ErrNotExist,
-			wantType: ANotExist,
+			name:     "special file (socket)",
+			info:     mockFileInfo{mode: fs.ModeSocket},
+			wantType: ATypeSocket,
 		},
 		{
-			name:     "not exist error",
-			info:     mockFileInfo{},
-			err:      os.ErrNotExist,
-			wantType: ANotExist,
+			name:     "special file (irregular)",
+			info:     mockFileInfo{mode: fs.ModeIrregular},
+			wantType: ATypeIrregular,
 		},
 		{
-			name:     "permission error",
-			info:     mockFileInfo{},
-			err:      os.ErrPermission,
-			wantType: ANotExist,
-			wantErr:  true,
-			errIs:    os.ErrPermission,
+			name:     "permission bits ignored",
+			info:     mockFileInfo{mode: fs.ModeDir | 0755},
+			wantType: ATypeDir,
 		},
 		{
-			name:     "generic error",
-			info:     mockFileInfo{},
-			err:      errors.New("something went wrong"),
+			name:     "nil FileInfo",
+			info:     nil,
 			wantType: ANotExist,
-			wantErr:  true,
 		},
 		{
 			name:     "symlink with extra bits",
@@ -97,18 +90,7 @@ func Test_fileInfoToAPathType(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotType, err := fileInfoToAPathType(tt.info, tt.err)
-
-			if tt.wantErr {
-				assert.Error(t, err)
-				if tt.errIs != nil {
-					assert.ErrorIs(t, err, tt.errIs)
-				}
-			} else {
-				assert.NoError(t, err)
-			}
-
-			assert.Equal(t, tt.wantType, gotType)
+			assert.Equal(t, tt.wantType, APathTypeOf(tt.info))
 		})
 	}
 }
@@ -125,9 +107,50 @@ func TestAPathType(t *testing.T) {
 		{ATypeDir, "Dir"},
 		{ATypeSymlink, "Symlink"},
 		{ATypeUnknown, "Unknown"},
+		{ATypeNamedPipe, "NamedPipe"},
+		{ATypeSocket, "Socket"},
+		{ATypeCharDevice, "CharDevice"},
+		{ATypeBlockDevice, "BlockDevice"},
+		{ATypeIrregular, "Irregular"},
+		{AInaccessible, "Inaccessible"},
+		{APathType(99), "Unknown"},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			assert.Equal(t, tc.name, tc.info.String())
 		})
 	}
 }
+
+func TestAPathTypeOfEntry(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, ANotExist, APathTypeOfEntry(nil))
+	assert.Equal(t, ANotExist, APathTypeOf(nil))
+	entry := fs.FileInfoToDirEntry(mockFileInfo{mode: fs.ModeSocket})
+	assert.Equal(t, ATypeSocket, APathTypeOfEntry(entry))
+}
+
+func TestAPathTypeSet(t *testing.T) {
+	t.Parallel()
+
+	set := NewAPathTypeSet(ATypeFile, ATypeDir)
+	assert.True(t, set.Has(ATypeFile))
+	assert.True(t, set.Has(ATypeDir))
+	assert.False(t, set.Has(ATypeSymlink))
+	assert.False(t, set.Has(APathType(99)))
+	assert.Equal(t, "File|Dir", set.String())
+
+	set = set.With(ATypeSymlink).Without(ATypeFile)
+	assert.Equal(t, "Dir|Symlink", set.String())
+	assert.True(t, set.Matches(&aPath{APiece: "/", aType: ATypeSymlink}))
+	assert.False(t, set.Matches(&aPath{APiece: "/"}))
+
+	assert.Equal(t, "None", ATypesNone.String())
+	assert.Equal(t, ATypeSocket.Set(), NewAPathTypeSet(ATypeSocket))
+	for kind := ANotExist; kind < numAPathTypes; kind++ {
+		assert.True(t, ATypesAll.Has(kind), kind.String())
+		assert.Equal(t, kind != ANotExist && kind != AInaccessible, ATypesExisting.Has(kind), kind.String())
+	}
+	assert.True(t, ATypesSpecial.Has(ATypeCharDevice))
+	assert.False(t, ATypesSpecial.Has(ATypeFile))
+}
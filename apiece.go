@@ -3,6 +3,7 @@ package apathy
 import (
 	"path"
 	"strings"
+	"unicode/utf8"
 )
 
 // APiece is a string intended for file-system path construction but with the
@@ -38,8 +39,15 @@ const WindowsDriveRootLen = len("C:/")
 
 
 // NewAPiece cleans the given string and ensures it is in posix-form,
-// regardless of the platform the code is running on.
+// regardless of the platform the code is running on. On Windows, a leading
+// "//server/share" is kept as a UNC prefix; elsewhere it's just a doubled slash.
 func NewAPiece(str string) APiece {
+	return newAPiece(str, true)
+}
+
+// newAPiece is NewAPiece, with the option of treating a leading "//" as just a
+// doubled slash, for strings we've assembled rather than been given.
+func newAPiece(str string, allowUNC bool) APiece {
 	str = ToSlash(str)
 
 	// For windows filepaths, an absolute drive root is `<letter>:/`, but clean
@@ -52,6 +60,16 @@ func NewAPiece(str string) APiece {
 		}
 	}
 
+	// UNC paths, //server/share/..., would lose their leading double-slash to
+	// path.Clean, and ".." mustn't take us above the share.
+	if share := hostUNCRoot(APiece(str)); share != "" && allowUNC {
+		rest := path.Clean("/" + str[share.Len():])
+		if rest == "/" {
+			return share
+		}
+		return share + APiece(rest)
+	}
+
 	// Ok, tidy away, and that's a piece.
 	return APiece(path.Clean(str))
 }
@@ -71,6 +89,13 @@ func (p APiece) Len() int {
 	return len(p)
 }
 
+// IsValidUTF8 returns true if the piece is valid UTF-8. APieces keep whatever bytes
+// they were made from, since Linux filenames needn't be UTF-8; see EscapeDisplay
+// for showing one that isn't.
+func (p APiece) IsValidUTF8() bool {
+	return utf8.ValidString(string(p))
+}
+
 // Helpers.
 
 // Simple drive-letter check, does not handle UNC paths or powershell mount names.
@@ -86,6 +111,36 @@ func hasAbsDrive(p APiece) bool {
 	return len(p) >= WindowsDriveRootLen && p[WindowsDriveRootLen-1] == '/' && hasDriveLetter(p)
 }
 
+// uncShareRoot returns the "//server/share" prefix of a UNC path, or "" if the
+// piece doesn't start with one.
+func uncShareRoot(p APiece) APiece {
+	if len(p) < 2 || p[0] != '/' || p[1] != '/' {
+		return ""
+	}
+	rest := p.String()[2:]
+	server := strings.IndexByte(rest, '/')
+	if server <= 0 || server == len(rest)-1 {
+		return ""
+	}
+	share := strings.IndexByte(rest[server+1:], '/')
+	if share < 0 {
+		return p
+	}
+	if share == 0 {
+		return ""
+	}
+	return p[:2+server+1+share]
+}
+
+// hostUNCRoot is uncShareRoot for paths on this machine: only Windows treats
+// "//server/share" as a volume, so elsewhere it always returns "".
+func hostUNCRoot(p APiece) APiece {
+	if NativeFlavour != FlavourWindows {
+		return ""
+	}
+	return uncShareRoot(p)
+}
+
 func (p APiece) IsAbs() bool {
 	if len(p) >= 1 && p[0] == '/' {
 		return true
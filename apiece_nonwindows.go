@@ -15,12 +15,7 @@ import "strings"
 // replaced.
 func (p APiece) Normalize() string {
 	if hasDriveLetter(p) {
-		return strings.Map(func(r rune) rune {
-			if r == '/' {
-				return '\\'
-			}
-			return r
-		}, p.String())
+		return strings.ReplaceAll(p.String(), "/", `\`)
 	}
 	return p.String()
 }
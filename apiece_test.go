@@ -31,6 +31,9 @@ func TestNewAPiece_Basic(t *testing.T) {
 		{"posixdriveroot", "x:/", "x:/"},
 		{"notepad", "c:\\windows\\notepad.exe", "c:/windows/notepad.exe"},
 		{"notemix", "c:/windows\\notepad.exe", "c:/windows/notepad.exe"},
+		{"not unc triple", "///etc//motd", "/etc/motd"},
+		{"not unc server only", "//server", "/server"},
+		{"not unc empty share", "//server//x", "/server/x"},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			result := NewAPiece(tc.input)
@@ -40,6 +43,26 @@ func TestNewAPiece_Basic(t *testing.T) {
 	}
 }
 
+func TestNewAPiece_UNC(t *testing.T) {
+	t.Parallel()
+	// Only Windows has UNC paths; elsewhere a leading "//" is just a doubled slash,
+	// and "//home/user" must be the same piece as "/home/user".
+	for _, tc := range []struct{ name, input, windows, posix string }{
+		{"unc", "\\\\server\\share\\dir\\..\\file", "//server/share/file", "/server/share/file"},
+		{"unc root", "//server/share/", "//server/share", "/server/share"},
+		{"unc clamped", "//server/share/../../x", "//server/share/x", "/x"},
+		{"doubled root", "//home/user", "//home/user", "/home/user"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			expected := tc.posix
+			if onWindows {
+				expected = tc.windows
+			}
+			assert.Equal(t, expected, NewAPiece(tc.input).String())
+		})
+	}
+}
+
 func TestAPiece_Piece(t *testing.T) {
 	t.Parallel()
 
@@ -74,6 +97,19 @@ func TestAPiece_Len(t *testing.T) {
 	}
 }
 
+func TestNewAPiece_NotUTF8(t *testing.T) {
+	t.Parallel()
+
+	// Linux filenames are just bytes, and we mustn't turn them into U+FFFD.
+	piece := NewAPiece("/srv/caf\xe9\\latin1/./\xff\xfe")
+	assert.Equal(t, APiece("/srv/caf\xe9/latin1/\xff\xfe"), piece)
+	assert.False(t, piece.IsValidUTF8())
+	assert.Equal(t, "/srv/caf\xe9/./\xff\xfe", ToSlash("\\srv\\caf\xe9\\.\\\xff\xfe"))
+
+	assert.True(t, APiece("/srv/caf\u00e9").IsValidUTF8())
+	assert.True(t, APiece("").IsValidUTF8())
+}
+
 func TestAPiece_IsAbs(t *testing.T) {
 	// We're not going to test cases where the piece has invalid data that
 	// doesn't meet our expectations. The string should be posix-styled.
@@ -117,6 +153,7 @@ func TestApiece_Normalize(t *testing.T) {
 		{"slash-etc-motd", "/etc/motd", nativeSep + "etc" + nativeSep + "motd"},
 		{"etc-motd", "etc/motd", "etc" + nativeSep + "motd"},
 		{"c-windows-notepad", "c:/windows/notepad.exe", "c:\\windows\\notepad.exe"},
+		{"not-utf8", "c:/caf\xe9/\xff\xfe", "c:\\caf\xe9\\\xff\xfe"},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			normalized := APiece(tc.input).Normalize()
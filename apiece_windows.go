@@ -12,10 +12,5 @@ import "strings"
 //
 // Check if you really need to do this before calling it a lot of times, m'kay?
 func (p APiece) Normalize() string {
-	return strings.Map(func (r rune) rune {
-		if r == '/' {
-			return '\\'
-		}
-		return r
-	}, p.String())
+	return strings.ReplaceAll(p.String(), "/", `\`)
 }
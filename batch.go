@@ -0,0 +1,96 @@
+package apathy
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// BatchOptions controls how NewAPaths goes about its business. The zero value is
+// a sensible default.
+type BatchOptions struct {
+	// Workers is the maximum number of paths being resolved and Lstat()d at once.
+	// Zero or less means four per available CPU, since the work is mostly waiting
+	// on the filesystem.
+	Workers int
+	// Tolerant makes Lstat failures other than NotExist produce AInaccessible APaths,
+	// as per NewAPathTolerant, rather than aborting the whole batch.
+	Tolerant bool
+}
+
+func (o BatchOptions) workers(jobs int) int {
+	workers := o.Workers
+	if workers <= 0 {
+		workers = 4 * runtime.GOMAXPROCS(0)
+	}
+	return min(workers, jobs)
+}
+
+// NewAPaths is the bulk form of NewAPath: each piece is resolved to an absolute
+// path and Lstat()d, using a bounded pool of workers. The results are in the same
+// order as the input. Inputs that are the same once cleaned, e.g. "a/b" and
+// "a/./b", are only resolved once, and share the same APath in the result.
+//
+// The first error (or cancellation of ctx) stops the batch, and is returned
+// without any results. Use BatchOptions.Tolerant to keep going past Lstat errors.
+func NewAPaths(ctx context.Context, pieces []APiece, opts BatchOptions) ([]APath, error) {
+	// firstOf maps each distinct cleaned input to the index of its first
+	// occurrence, and those indexes are the only jobs we hand out.
+	cleaned := make([]APiece, len(pieces))
+	firstOf := make(map[APiece]int, len(pieces))
+	jobs := make([]int, 0, len(pieces))
+	for idx, piece := range pieces {
+		cleaned[idx] = NewAPiece(piece.String())
+		if _, seen := firstOf[cleaned[idx]]; !seen {
+			firstOf[cleaned[idx]] = idx
+			jobs = append(jobs, idx)
+		}
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	newAPath := NewAPath
+	if opts.Tolerant {
+		newAPath = NewAPathTolerant
+	}
+
+	// Each job writes to a distinct index of results, so no locking is needed.
+	results := make([]APath, len(pieces))
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for workers := opts.workers(len(jobs)); workers > 0; workers-- {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range work {
+				apath, err := newAPath(pieces[idx])
+				if err != nil {
+					cancel(err)
+					return
+				}
+				results[idx] = apath
+			}
+		}()
+	}
+
+feed:
+	for _, idx := range jobs {
+		select {
+		case work <- idx:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(work)
+	wg.Wait()
+
+	if err := context.Cause(ctx); err != nil {
+		return nil, err
+	}
+
+	for idx := range pieces {
+		results[idx] = results[firstOf[cleaned[idx]]]
+	}
+	return results, nil
+}
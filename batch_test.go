@@ -0,0 +1,100 @@
+package apathy
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAPaths(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file"), []byte("hello"), 0644))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "sub"), 0755))
+	root := NewAPiece(dir)
+
+	pieces := []APiece{
+		Join(root, "file"),
+		Join(root, "sub"),
+		Join(root, "missing"),
+		Join(root, "file"),
+		root + "//file",
+		root + "/./file",
+	}
+	for _, workers := range []int{0, 1, 3} {
+		results, err := NewAPaths(context.Background(), pieces, BatchOptions{Workers: workers})
+		require.NoError(t, err)
+		require.Len(t, results, len(pieces))
+		for idx, result := range results {
+			assert.Equal(t, NewAPiece(pieces[idx].String()), result.Piece())
+		}
+		assert.True(t, results[0].IsFile())
+		assert.Equal(t, int64(5), results[0].Size())
+		assert.True(t, results[1].IsDir())
+		assert.False(t, results[2].Exists())
+		assert.Same(t, results[0], results[3], "duplicates should share an APath")
+		assert.Same(t, results[0], results[4], "so should inputs that clean the same")
+		assert.Same(t, results[0], results[5], "so should inputs that clean the same")
+	}
+
+	results, err := NewAPaths(context.Background(), nil, BatchOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func TestNewAPaths_Cancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results, err := NewAPaths(ctx, []APiece{"/a", "/b", "/c"}, BatchOptions{Workers: 1})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, results)
+}
+
+func TestNewAPaths_Errors(t *testing.T) {
+	// Can't be parallel because it modifies globals.
+	var lstatErr = errors.New("no cookie for you")
+	defer withSaved(&Lstat, func(in string) (os.FileInfo, error) {
+		if in == "/jar" {
+			return nil, lstatErr
+		}
+		return mockFileInfo{mode: 0644}, nil
+	})()
+	pieces := []APiece{"/plate", "/jar", "/plate"}
+
+	results, err := NewAPaths(context.Background(), pieces, BatchOptions{})
+	assert.ErrorIs(t, err, lstatErr)
+	assert.Nil(t, results)
+
+	results, err = NewAPaths(context.Background(), pieces, BatchOptions{Tolerant: true})
+	require.NoError(t, err)
+	assert.True(t, results[0].IsFile())
+	assert.Equal(t, AInaccessible, results[1].Type())
+	assert.ErrorIs(t, results[1].Err(), lstatErr)
+}
+
+func TestNewAPaths_Cleaned(t *testing.T) {
+	// Can't be parallel because it modifies globals.
+	var lstats []string
+	var mu sync.Mutex
+	defer withSaved(&Lstat, func(in string) (os.FileInfo, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		lstats = append(lstats, in)
+		return mockFileInfo{mode: 0644}, nil
+	})()
+
+	results, err := NewAPaths(context.Background(), []APiece{"/a/b", "/a//b", "/a/./b"}, BatchOptions{})
+	require.NoError(t, err)
+	assert.Len(t, lstats, 1, "spellings of the same path should only be Lstat()d once")
+	assert.Same(t, results[0], results[1])
+	assert.Same(t, results[0], results[2])
+}
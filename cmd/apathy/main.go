@@ -0,0 +1,108 @@
+// Command apathy exposes some of the apathy library's tools from the command line.
+//
+//	apathy lint [-platform linux,darwin,windows] [-windows-root C:/build] [dir ...]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	apathy "github.com/kfsone/go-apathy"
+)
+
+// Exit codes: success, problems found, and couldn't do what was asked.
+const (
+	exitOK     = 0
+	exitIssues = 1
+	exitUsage  = 2
+)
+
+type command struct {
+	summary string
+	run     func(args []string, stdout, stderr io.Writer) int
+}
+
+var commands = map[string]command{
+	"lint": {"report names and paths that won't survive a move to another platform", lint},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return exitUsage
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "apathy: unknown command %q\n", args[0])
+		usage(stderr)
+		return exitUsage
+	}
+	return cmd.run(args[1:], stdout, stderr)
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: apathy <command> [arguments]")
+	fmt.Fprintln(w, "commands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-8s %s\n", name, commands[name].summary)
+	}
+}
+
+func lint(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("apathy lint", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	platforms := flags.String("platform", "", "comma-separated platforms to check against (default all)")
+	var windowsRoot apathy.PieceFlag
+	flags.Var(&windowsRoot, "windows-root", "where the tree will live on Windows, for path lengths")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	var opts apathy.LintOptions
+	opts.WindowsRoot = windowsRoot.Piece
+	if *platforms != "" {
+		for _, name := range strings.Split(*platforms, ",") {
+			platform, err := apathy.ParsePlatform(strings.TrimSpace(name))
+			if err != nil {
+				fmt.Fprintf(stderr, "apathy lint: %v\n", err)
+				return exitUsage
+			}
+			opts.Platforms = append(opts.Platforms, platform)
+		}
+	}
+
+	dirs := flags.Args()
+	if len(dirs) == 0 {
+		dirs = []string{"."}
+	}
+	status := exitOK
+	for _, dir := range dirs {
+		root, err := apathy.NewAPath(apathy.NewAPiece(dir))
+		if err == nil {
+			var issues []apathy.LintIssue
+			issues, err = apathy.Lint(root, opts)
+			for _, issue := range issues {
+				fmt.Fprintf(stdout, "%s: %s\n", dir, issue)
+				status = max(status, exitIssues)
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "apathy lint: %v\n", err)
+			status = exitUsage
+		}
+	}
+	return status
+}
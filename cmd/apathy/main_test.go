@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitUsage, run(nil, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "lint")
+
+	stderr.Reset()
+	assert.Equal(t, exitUsage, run([]string{"frobnicate"}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), `unknown command "frobnicate"`)
+	assert.Empty(t, stdout.String())
+}
+
+func TestLint(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "nul.txt"), nil, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ok.txt"), nil, 0644))
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitIssues, run([]string{"lint", dir}, &stdout, &stderr))
+	assert.Equal(t, dir+`: nul.txt: windows: reserved name: "nul.txt"`+"\n", stdout.String())
+	assert.Empty(t, stderr.String())
+
+	stdout.Reset()
+	assert.Equal(t, exitOK, run([]string{"lint", "-platform", "linux,macos", dir}, &stdout, &stderr))
+	assert.Empty(t, stdout.String())
+	assert.Empty(t, stderr.String())
+
+	assert.Equal(t, exitUsage, run([]string{"lint", "-platform", "beos", dir}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), `"beos"`)
+
+	stderr.Reset()
+	assert.Equal(t, exitUsage, run([]string{"lint", filepath.Join(dir, "ok.txt")}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "not a directory")
+}
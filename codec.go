@@ -0,0 +1,294 @@
+package apathy
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"sort"
+	"time"
+)
+
+// The binary encoding is a header followed by one record per APath:
+//
+//	uvarint  length of the prefix shared with the previous path
+//	uvarint  length of the rest of the path
+//	bytes    the rest of the path
+//	byte     APathType
+//	if the path existed:
+//	  varint   size
+//	  varint   mtime seconds, as a delta from the previous record's
+//	  uvarint  mtime nanoseconds
+//	  uvarint  permission bits
+//	if the path was AInaccessible:
+//	  byte     1 if there was an error, 0 if not
+//	  if there was an error:
+//	    uvarint  length of the error message
+//	    bytes    the error message
+//
+// Prefix compression is what makes this compact, so paths should be written in
+// sorted order; MarshalAPaths takes care of that.
+const (
+	codecMagic   = "APTH"
+	codecVersion = 1
+	// codecMaxLen bounds the length fields so a corrupt stream can't make us allocate the world.
+	codecMaxLen = 1 << 20
+)
+
+// APathWriter streams APaths to an io.Writer in the compact binary encoding. Call
+// Flush when done.
+type APathWriter struct {
+	w         *bufio.Writer
+	prev      APiece
+	prevMtime int64
+	started   bool
+	err       error
+	scratch   [binary.MaxVarintLen64]byte
+}
+
+// NewAPathWriter returns an APathWriter that writes to w.
+func NewAPathWriter(w io.Writer) *APathWriter {
+	return &APathWriter{w: bufio.NewWriter(w)}
+}
+
+// Write appends an APath to the stream.
+func (w *APathWriter) Write(p APath) error {
+	if w.err != nil {
+		return w.err
+	}
+	if !w.started {
+		w.started = true
+		w.w.WriteString(codecMagic)
+		w.w.WriteByte(codecVersion)
+	}
+
+	piece := p.Piece()
+	shared := commonPrefixLen(w.prev, piece)
+	w.putUvarint(uint64(shared))
+	w.putString(string(piece[shared:]))
+	w.prev = piece
+
+	aType := p.Type()
+	w.w.WriteByte(byte(aType))
+	switch aType {
+	case ANotExist:
+	case AInaccessible:
+		if err := p.Err(); err != nil {
+			w.w.WriteByte(1)
+			w.putString(err.Error())
+		} else {
+			w.w.WriteByte(0)
+		}
+	default:
+		mtime := p.ModTime()
+		w.putVarint(p.Size())
+		w.putVarint(mtime.Unix() - w.prevMtime)
+		w.putUvarint(uint64(mtime.Nanosecond()))
+		w.prevMtime = mtime.Unix()
+		var perm fs.FileMode
+		if permed, ok := p.(Permissioned); ok {
+			perm = permed.Perm()
+		}
+		w.putUvarint(uint64(perm))
+	}
+	// bufio.Writer remembers the first error, so we only need to check once.
+	_, w.err = w.w.Write(nil)
+	return w.err
+}
+
+// Flush writes any buffered data to the underlying io.Writer.
+func (w *APathWriter) Flush() error {
+	if w.err != nil {
+		return w.err
+	}
+	w.err = w.w.Flush()
+	return w.err
+}
+
+func (w *APathWriter) putUvarint(value uint64) {
+	w.w.Write(w.scratch[:binary.PutUvarint(w.scratch[:], value)])
+}
+
+func (w *APathWriter) putVarint(value int64) {
+	w.w.Write(w.scratch[:binary.PutVarint(w.scratch[:], value)])
+}
+
+func (w *APathWriter) putString(str string) {
+	w.putUvarint(uint64(len(str)))
+	w.w.WriteString(str)
+}
+
+// APathReader reads APaths written by an APathWriter, rebuilding them from the
+// recorded metadata without re-stating.
+type APathReader struct {
+	r         *bufio.Reader
+	prev      []byte
+	prevMtime int64
+	started   bool
+}
+
+// NewAPathReader returns an APathReader that reads from r.
+func NewAPathReader(r io.Reader) *APathReader {
+	return &APathReader{r: bufio.NewReader(r)}
+}
+
+// Read returns the next APath in the stream, or io.EOF when there are no more.
+func (r *APathReader) Read() (APath, error) {
+	if !r.started {
+		var header [len(codecMagic) + 1]byte
+		if _, err := io.ReadFull(r.r, header[:]); err != nil {
+			if errors.Is(err, io.EOF) {
+				// An empty stream is an empty collection.
+				return nil, io.EOF
+			}
+			return nil, unexpected(err)
+		}
+		if string(header[:len(codecMagic)]) != codecMagic || header[len(codecMagic)] != codecVersion {
+			return nil, fmt.Errorf("%w: bad header", ErrBadEncoding)
+		}
+		r.started = true
+	}
+
+	shared, err := binary.ReadUvarint(r.r)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		return nil, unexpected(err)
+	}
+	if shared > uint64(len(r.prev)) {
+		return nil, fmt.Errorf("%w: prefix longer than previous path", ErrBadEncoding)
+	}
+	suffix, err := r.getBytes()
+	if err != nil {
+		return nil, err
+	}
+	// Don't append to r.prev in place: earlier APieces may share its storage.
+	path := make([]byte, 0, int(shared)+len(suffix))
+	path = append(append(path, r.prev[:shared]...), suffix...)
+	r.prev = path
+
+	p := &aPath{APiece: APiece(path)}
+	if !p.APiece.IsAbs() {
+		return nil, &PathError{Op: "APathReader.Read", Piece: p.APiece, Err: ErrNotAbsolute}
+	}
+	typeByte, err := r.r.ReadByte()
+	if err != nil {
+		return nil, unexpected(err)
+	}
+	p.aType = APathType(typeByte)
+	switch p.aType {
+	case ANotExist:
+	case AInaccessible:
+		hasErr, err := r.r.ReadByte()
+		if err != nil {
+			return nil, unexpected(err)
+		}
+		switch hasErr {
+		case 0:
+		case 1:
+			msg, err := r.getBytes()
+			if err != nil {
+				return nil, err
+			}
+			p.err = errors.New(string(msg))
+		default:
+			return nil, fmt.Errorf("%w: bad error flag %d", ErrBadEncoding, hasErr)
+		}
+	default:
+		if p.aType >= numAPathTypes {
+			return nil, fmt.Errorf("%w: bad type %d", ErrBadEncoding, typeByte)
+		}
+		if p.size, err = binary.ReadVarint(r.r); err != nil {
+			return nil, unexpected(err)
+		}
+		delta, err := binary.ReadVarint(r.r)
+		if err != nil {
+			return nil, unexpected(err)
+		}
+		nsec, err := binary.ReadUvarint(r.r)
+		if err != nil {
+			return nil, unexpected(err)
+		}
+		perm, err := binary.ReadUvarint(r.r)
+		if err != nil {
+			return nil, unexpected(err)
+		}
+		r.prevMtime += delta
+		p.mtime = time.Unix(r.prevMtime, int64(nsec))
+		p.perm = fs.FileMode(perm).Perm()
+	}
+	return p, nil
+}
+
+func (r *APathReader) getBytes() ([]byte, error) {
+	length, err := binary.ReadUvarint(r.r)
+	if err != nil {
+		return nil, unexpected(err)
+	}
+	if length > codecMaxLen {
+		return nil, fmt.Errorf("%w: length %d too long", ErrBadEncoding, length)
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r.r, data); err != nil {
+		return nil, unexpected(err)
+	}
+	return data, nil
+}
+
+// unexpected promotes EOF to ErrUnexpectedEOF for when we're part-way through a record.
+func unexpected(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// MarshalAPaths encodes a collection of APaths, sorted by path for the best
+// compression. The input slice is not modified.
+func MarshalAPaths(paths []APath) ([]byte, error) {
+	sorted := append([]APath(nil), paths...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Piece() < sorted[j].Piece()
+	})
+	var buf bytes.Buffer
+	w := NewAPathWriter(&buf)
+	for _, p := range sorted {
+		if err := w.Write(p); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalAPaths decodes the output of MarshalAPaths.
+func UnmarshalAPaths(data []byte) ([]APath, error) {
+	var paths []APath
+	r := NewAPathReader(bytes.NewReader(data))
+	for {
+		p, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return paths, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, p)
+	}
+}
+
+func commonPrefixLen(a, b APiece) int {
+	n := min(len(a), len(b))
+	for i := 0; i < n; i++ {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	return n
+}
@@ -0,0 +1,128 @@
+package apathy
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPathCodec_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	mtime := time.Date(2025, 2, 5, 12, 34, 56, 789, time.UTC)
+	in := []APath{
+		&aPath{APiece: "/assets/textures/b.png", aType: ATypeFile, mtime: mtime, size: 1 << 40, perm: 0644},
+		&aPath{APiece: "/assets/textures/a.png", aType: ATypeFile, mtime: mtime.Add(-time.Hour), size: 12, perm: 0600},
+		&aPath{APiece: "/assets", aType: ATypeDir, mtime: mtime, perm: 0755},
+		&aPath{APiece: "/assets/gone"},
+		&aPath{APiece: "c:/secret", aType: AInaccessible, err: errors.New("access denied")},
+		&aPath{APiece: "/dev/null", aType: ATypeCharDevice},
+		&aPath{APiece: "c:/unknown", aType: AInaccessible},
+	}
+	data, err := MarshalAPaths(in)
+	require.NoError(t, err)
+	assert.Equal(t, APiece("/assets/textures/b.png"), in[0].Piece(), "input should not be reordered")
+
+	out, err := UnmarshalAPaths(data)
+	require.NoError(t, err)
+	require.Len(t, out, len(in))
+
+	want := []int{2, 3, 1, 0, 5, 4, 6}
+	for idx, p := range out {
+		expect := in[want[idx]]
+		assert.Equal(t, expect.Piece(), p.Piece())
+		assert.Equal(t, expect.Type(), p.Type())
+		assert.Equal(t, expect.Size(), p.Size())
+		assert.True(t, expect.ModTime().Equal(p.ModTime()), "%s: %s vs %s", p, expect.ModTime(), p.ModTime())
+		assert.Equal(t, expect.(Permissioned).Perm(), p.(Permissioned).Perm())
+		if expect.Err() != nil {
+			assert.EqualError(t, p.Err(), expect.Err().Error())
+		} else {
+			assert.NoError(t, p.Err(), "no error should stay no error")
+		}
+	}
+
+	// The shared "/assets" prefixes mean we should be well under the raw path lengths.
+	var rawLen int
+	for _, p := range in {
+		rawLen += p.Len()
+	}
+	assert.Less(t, len(data), rawLen+len(in)*8)
+}
+
+func TestAPathCodec_Empty(t *testing.T) {
+	t.Parallel()
+
+	data, err := MarshalAPaths(nil)
+	require.NoError(t, err)
+	assert.Empty(t, data)
+	out, err := UnmarshalAPaths(data)
+	assert.NoError(t, err)
+	assert.Empty(t, out)
+}
+
+func TestAPathReader_Streaming(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	w := NewAPathWriter(&buf)
+	require.NoError(t, w.Write(&aPath{APiece: "/a", aType: ATypeDir}))
+	require.NoError(t, w.Write(&aPath{APiece: "/a/b"}))
+	require.NoError(t, w.Flush())
+
+	r := NewAPathReader(&buf)
+	p, err := r.Read()
+	require.NoError(t, err)
+	assert.Equal(t, "/a", p.String())
+	p, err = r.Read()
+	require.NoError(t, err)
+	assert.Equal(t, "/a/b", p.String())
+	_, err = r.Read()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestAPathReader_Malformed(t *testing.T) {
+	t.Parallel()
+
+	valid, err := MarshalAPaths([]APath{&aPath{APiece: "/x", aType: ATypeFile, mtime: fixedTime, size: 1}})
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"bad magic", []byte("NOPE\x01"), ErrBadEncoding},
+		{"short header", []byte("AP"), io.ErrUnexpectedEOF},
+		{"truncated", valid[:len(valid)-2], io.ErrUnexpectedEOF},
+		{"bad prefix", []byte("APTH\x01\x05"), ErrBadEncoding},
+		{"relative", []byte("APTH\x01\x00\x01x\x00"), ErrNotAbsolute},
+		{"bad type", []byte("APTH\x01\x00\x01/\x63"), ErrBadEncoding},
+		{"huge", []byte("APTH\x01\x00\xff\xff\xff\xff\x0f"), ErrBadEncoding},
+		{"bad error flag", []byte("APTH\x01\x00\x01/\x0a\x02"), ErrBadEncoding},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := UnmarshalAPaths(tc.data)
+			assert.ErrorIs(t, err, tc.want)
+		})
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, io.ErrShortWrite }
+
+func TestAPathWriter_Error(t *testing.T) {
+	t.Parallel()
+
+	w := NewAPathWriter(failingWriter{})
+	assert.NoError(t, w.Write(&aPath{APiece: "/small"}))
+	assert.ErrorIs(t, w.Flush(), io.ErrShortWrite)
+	assert.ErrorIs(t, w.Write(&aPath{APiece: "/more"}), io.ErrShortWrite)
+	assert.ErrorIs(t, w.Flush(), io.ErrShortWrite)
+}
@@ -0,0 +1,101 @@
+package apathy
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Collation describes when a filesystem considers two names to be the same, so
+// that "Textures/A.png" and "textures/a.png" can be recognized as one file on
+// NTFS. Use Platform.Collation for the usual settings, or build one to match a
+// particular volume.
+type Collation struct {
+	// FoldCase makes names that differ only by case equal.
+	FoldCase bool
+	// Unicode makes names equal if they're equal once normalized to this form.
+	Unicode UnicodeForm
+}
+
+var (
+	CollationExact   = Collation{}                                    // CollationExact compares bytes, as ext4 and friends do.
+	CollationFold    = Collation{FoldCase: true}                      // CollationFold ignores case, as NTFS and FAT do.
+	CollationFoldNFD = Collation{FoldCase: true, Unicode: UnicodeNFD} // CollationFoldNFD ignores case and normalization, as APFS does.
+)
+
+// Key returns a canonical form of the piece under the collation, such that two
+// pieces are Equal if and only if their Keys are. It's meant for map keys and
+// sorting rather than display: case is folded to a single representative, which
+// isn't necessarily lower-case. Bytes that aren't valid UTF-8 are left alone.
+func (c Collation) Key(piece Piecer) APiece {
+	str := c.Unicode.normalize(piece.Piece().String())
+	if c.FoldCase {
+		str = foldCase(str)
+	}
+	return APiece(str)
+}
+
+// Equal returns true if the pieces name the same thing under the collation.
+func (c Collation) Equal(a, b Piecer) bool {
+	pa, pb := a.Piece(), b.Piece()
+	return pa == pb || c.Key(pa) == c.Key(pb)
+}
+
+// HasPrefix returns true if prefix is piece, or one of its parent directories,
+// under the collation. Only whole components match: "/a/bc" doesn't have the
+// prefix "/a/b".
+func (c Collation) HasPrefix(piece, prefix Piecer) bool {
+	_, ok := trimBase(c.Key(prefix), c.Key(piece))
+	return ok
+}
+
+// EqualFold returns true if the pieces differ only by case.
+func EqualFold(a, b Piecer) bool {
+	return CollationFold.Equal(a, b)
+}
+
+// HasPrefixFold is HasPrefix for a case-insensitive filesystem; see
+// Collation.HasPrefix.
+func HasPrefixFold(piece, prefix Piecer) bool {
+	return CollationFold.HasPrefix(piece, prefix)
+}
+
+// foldCase replaces each rune by the lowest-numbered rune it's equivalent to under
+// Unicode simple case folding, so "a" and "A" both become "A", and "ſ", "s" and
+// "S" all become "S". The folding comes from the unicode package, so it's as up
+// to date as the Go release; the normalization tables should match it.
+func foldCase(str string) string {
+	var buf strings.Builder
+	for idx := 0; idx < len(str); {
+		r, size := utf8.DecodeRuneInString(str[idx:])
+		folded := r
+		if size > 1 || r < utf8.RuneSelf {
+			folded = foldRune(r)
+		}
+		if folded != r && buf.Cap() == 0 {
+			// First change: copy what we've skipped so far.
+			buf.Grow(len(str))
+			buf.WriteString(str[:idx])
+		}
+		if buf.Cap() > 0 {
+			if folded != r {
+				buf.WriteRune(folded)
+			} else {
+				buf.WriteString(str[idx : idx+size])
+			}
+		}
+		idx += size
+	}
+	if buf.Cap() == 0 {
+		return str
+	}
+	return buf.String()
+}
+
+func foldRune(r rune) rune {
+	folded := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		folded = min(folded, f)
+	}
+	return folded
+}
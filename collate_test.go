@@ -0,0 +1,67 @@
+package apathy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollation_Key(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, APiece("Textures/A.png"), CollationExact.Key(APiece("Textures/A.png")))
+	assert.Equal(t, APiece("TEXTURES/A.PNG"), CollationFold.Key(APiece("Textures/a.png")))
+	// Folding is to the lowest rune in the case orbit, whatever its case.
+	assert.Equal(t, APiece("S/K/\u00c9"), CollationFold.Key(APiece("\u017f/\u212a/\u00e9")))
+	// Invalid UTF-8 is untouched.
+	assert.Equal(t, APiece("AB\xffC"), CollationFold.Key(APiece("ab\xffc")))
+	// Normalization happens before folding.
+	assert.Equal(t, APiece("CAFE\u0301"), CollationFoldNFD.Key(APiece("caf\u00e9")))
+	assert.Equal(t, APiece("caf\u00e9"), Collation{Unicode: UnicodeNFC}.Key(APiece("cafe\u0301")))
+
+	// Keys work as map keys.
+	seen := map[APiece]bool{}
+	for _, name := range []APiece{"Textures/A.png", "textures/a.png", "TEXTURES/a.PNG"} {
+		seen[CollationFold.Key(name)] = true
+	}
+	assert.Len(t, seen, 1)
+}
+
+func TestCollation_Equal(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, EqualFold(APiece("C:/Users/Me"), APiece("c:/users/ME")))
+	assert.False(t, EqualFold(APiece("/a/b"), APiece("/a/b/c")))
+	assert.False(t, CollationExact.Equal(APiece("a"), APiece("A")))
+	assert.False(t, CollationFold.Equal(APiece("caf\u00e9"), APiece("cafe\u0301")))
+	assert.True(t, CollationFoldNFD.Equal(APiece("CAF\u00c9"), APiece("cafe\u0301")))
+}
+
+func TestCollation_HasPrefix(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		piece, prefix APiece
+		expected      bool
+	}{
+		{"/a/b/c", "/A/B", true},
+		{"/a/b", "/A/B", true},
+		{"/a/bc", "/A/B", false},
+		{"/a", "/A/B", false},
+		{"/a/b", "/", true},
+		{"C:/Work/x", "c:/", true},
+		{"C:/Work/x", "c:/work", true},
+		{"a/b", "A", true},
+	} {
+		assert.Equal(t, tc.expected, HasPrefixFold(tc.piece, tc.prefix), "%s has prefix %s", tc.piece, tc.prefix)
+	}
+	assert.False(t, CollationExact.HasPrefix(APiece("/a/b"), APiece("/A")))
+}
+
+func TestPlatform_Collation(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, CollationExact, PlatformLinux.Collation())
+	assert.Equal(t, CollationFold, PlatformWindows.Collation())
+	assert.Equal(t, CollationFoldNFD, PlatformDarwin.Collation())
+}
@@ -0,0 +1,99 @@
+package apathy
+
+import (
+	"fmt"
+)
+
+// HomeDir returns an APath for the current user's home directory according to env.
+// A nil env means OSEnvironment.
+func HomeDir(env Environment) (APath, error) {
+	home, err := homePiece(env)
+	if err != nil {
+		return nil, err
+	}
+	return NewAPath(home)
+}
+
+// TempDir returns an APath for the temporary directory according to env, following
+// the same rules as os.TempDir: $TMPDIR or /tmp on posix; %TMP%, %TEMP%,
+// %USERPROFILE% or the Windows directory on Windows.
+func TempDir(env Environment) (APath, error) {
+	return NewAPath(tempDirPiece(env, NativeFlavour))
+}
+
+func tempDirPiece(env Environment, flavour Flavour) APiece {
+	env = envOrOS(env)
+	if flavour == FlavourWindows {
+		for _, key := range []string{"TMP", "TEMP", "USERPROFILE"} {
+			if dir, ok := env.LookupEnv(key); ok && dir != "" {
+				return NewAPiece(dir)
+			}
+		}
+		if root, ok := env.LookupEnv("SystemRoot"); ok && root != "" {
+			return NewAPiece(root)
+		}
+		return "C:/Windows"
+	}
+	if dir, ok := env.LookupEnv("TMPDIR"); ok && dir != "" {
+		return NewAPiece(dir)
+	}
+	return "/tmp"
+}
+
+// XDGConfigHome returns $XDG_CONFIG_HOME, defaulting to ~/.config.
+func XDGConfigHome(env Environment) (APath, error) {
+	return xdgDir(env, "XDG_CONFIG_HOME", ".config")
+}
+
+// XDGCacheHome returns $XDG_CACHE_HOME, defaulting to ~/.cache.
+func XDGCacheHome(env Environment) (APath, error) {
+	return xdgDir(env, "XDG_CACHE_HOME", ".cache")
+}
+
+// XDGDataHome returns $XDG_DATA_HOME, defaulting to ~/.local/share.
+func XDGDataHome(env Environment) (APath, error) {
+	return xdgDir(env, "XDG_DATA_HOME", ".local/share")
+}
+
+// XDGStateHome returns $XDG_STATE_HOME, defaulting to ~/.local/state.
+func XDGStateHome(env Environment) (APath, error) {
+	return xdgDir(env, "XDG_STATE_HOME", ".local/state")
+}
+
+// XDGRuntimeDir returns $XDG_RUNTIME_DIR. The spec doesn't provide a default for
+// this one, so if it isn't set, you get ErrUndefinedVariable.
+func XDGRuntimeDir(env Environment) (APath, error) {
+	return xdgDir(env, "XDG_RUNTIME_DIR", "")
+}
+
+// xdgDir implements the XDG base directory rules: use the variable if it holds an
+// absolute path, otherwise fall back to the default beneath the home directory.
+func xdgDir(env Environment, key string, fallback APiece) (APath, error) {
+	env = envOrOS(env)
+	if value, ok := env.LookupEnv(key); ok {
+		// The spec says relative paths are invalid and should be ignored.
+		if dir := NewAPiece(value); dir.IsAbs() {
+			return NewAPath(dir)
+		}
+	}
+	if fallback == "" {
+		return nil, fmt.Errorf("%w: %s", ErrUndefinedVariable, key)
+	}
+	home, err := homePiece(env)
+	if err != nil {
+		return nil, err
+	}
+	return NewAPath(home, fallback)
+}
+
+func homePiece(env Environment) (APiece, error) {
+	home, err := envOrOS(env).HomeDir("")
+	if err != nil {
+		return "", err
+	}
+	piece := NewAPiece(home)
+	if !piece.IsAbs() {
+		return "", &PathError{Op: "HomeDir", Piece: piece, Err: ErrNotAbsolute}
+	}
+	return piece, nil
+}
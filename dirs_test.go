@@ -0,0 +1,97 @@
+package apathy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestXDGDirs(t *testing.T) {
+	t.Parallel()
+
+	home := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(home, ".config"), 0755))
+	homePiece := NewAPiece(home)
+	elsewhere := NewAPiece(t.TempDir())
+
+	defaults := MapEnvironment{Vars: map[string]string{"HOME": home}}
+	overridden := MapEnvironment{Vars: map[string]string{
+		"HOME":            home,
+		"XDG_CONFIG_HOME": elsewhere.String(),
+		"XDG_CACHE_HOME":  "relative/is/ignored",
+		"XDG_RUNTIME_DIR": elsewhere.String(),
+	}}
+
+	for _, tc := range []struct {
+		name     string
+		fn       func(Environment) (APath, error)
+		env      Environment
+		expected APiece
+	}{
+		{"config", XDGConfigHome, defaults, Join(homePiece, ".config")},
+		{"cache", XDGCacheHome, defaults, Join(homePiece, ".cache")},
+		{"data", XDGDataHome, defaults, Join(homePiece, ".local/share")},
+		{"state", XDGStateHome, defaults, Join(homePiece, ".local/state")},
+		{"config override", XDGConfigHome, overridden, elsewhere},
+		{"cache relative", XDGCacheHome, overridden, Join(homePiece, ".cache")},
+		{"runtime", XDGRuntimeDir, overridden, elsewhere},
+		{"home", HomeDir, defaults, homePiece},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := tc.fn(tc.env)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, p.Piece())
+		})
+	}
+
+	// And they've been stat'd.
+	p, err := XDGConfigHome(defaults)
+	require.NoError(t, err)
+	assert.True(t, p.IsDir())
+	p, err = XDGCacheHome(defaults)
+	require.NoError(t, err)
+	assert.False(t, p.Exists())
+}
+
+func TestXDGDirs_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := XDGRuntimeDir(MapEnvironment{})
+	assert.ErrorIs(t, err, ErrUndefinedVariable)
+	_, err = XDGConfigHome(MapEnvironment{})
+	assert.Error(t, err)
+	_, err = HomeDir(MapEnvironment{Vars: map[string]string{"HOME": "relative"}})
+	assert.ErrorIs(t, err, ErrNotAbsolute)
+}
+
+func TestTempDir(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		vars     map[string]string
+		flavour  Flavour
+		expected APiece
+	}{
+		{"posix default", nil, FlavourPosix, "/tmp"},
+		{"posix TMPDIR", map[string]string{"TMPDIR": "/var/tmp/"}, FlavourPosix, "/var/tmp"},
+		{"windows TMP", map[string]string{"TMP": `C:\t1`, "TEMP": `C:\t2`}, FlavourWindows, "C:/t1"},
+		{"windows TEMP", map[string]string{"TMP": "", "TEMP": `C:\t2`}, FlavourWindows, "C:/t2"},
+		{"windows profile", map[string]string{"USERPROFILE": `C:\Users\me`}, FlavourWindows, "C:/Users/me"},
+		{"windows root", map[string]string{"SystemRoot": `D:\Windows`}, FlavourWindows, "D:/Windows"},
+		{"windows default", nil, FlavourWindows, "C:/Windows"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tempDirPiece(MapEnvironment{Vars: tc.vars}, tc.flavour))
+		})
+	}
+
+	tmp := t.TempDir()
+	p, err := TempDir(MapEnvironment{Vars: map[string]string{"TMPDIR": tmp, "TMP": tmp}})
+	require.NoError(t, err)
+	assert.Equal(t, NewAPiece(tmp), p.Piece())
+	assert.True(t, p.IsDir())
+}
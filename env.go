@@ -0,0 +1,72 @@
+package apathy
+
+import (
+	"fmt"
+	"os/user"
+)
+
+// Environment supplies the variables and home directories that Expand and the
+// directory helpers consult, so that tests and sandboxes can substitute their own.
+// A nil Environment means OSEnvironment.
+type Environment interface {
+	// LookupEnv behaves like os.LookupEnv.
+	LookupEnv(key string) (string, bool)
+	// HomeDir returns the home directory of the named user, or of the current
+	// user if username is empty.
+	HomeDir(username string) (string, error)
+}
+
+// OSEnvironment is the Environment of the running process, by way of the
+// package's LookupEnv and UserHomeDir hooks.
+type OSEnvironment struct{}
+
+func (OSEnvironment) LookupEnv(key string) (string, bool) {
+	return LookupEnv(key)
+}
+
+func (OSEnvironment) HomeDir(username string) (string, error) {
+	if username == "" {
+		return UserHomeDir()
+	}
+	u, err := LookupUser(username)
+	if err != nil {
+		return "", err
+	}
+	return u.HomeDir, nil
+}
+
+// MapEnvironment is an Environment backed by maps, for tests and sandboxes.
+type MapEnvironment struct {
+	// Vars holds the environment variables.
+	Vars map[string]string
+	// Homes maps user names to home directories. The current user's home is
+	// Homes[""], falling back to $HOME and then $USERPROFILE.
+	Homes map[string]string
+}
+
+func (m MapEnvironment) LookupEnv(key string) (string, bool) {
+	value, ok := m.Vars[key]
+	return value, ok
+}
+
+func (m MapEnvironment) HomeDir(username string) (string, error) {
+	if home, ok := m.Homes[username]; ok {
+		return home, nil
+	}
+	if username == "" {
+		for _, key := range []string{"HOME", "USERPROFILE"} {
+			if home, ok := m.Vars[key]; ok && home != "" {
+				return home, nil
+			}
+		}
+		return "", fmt.Errorf("%w: no home directory", ErrUndefinedVariable)
+	}
+	return "", user.UnknownUserError(username)
+}
+
+func envOrOS(env Environment) Environment {
+	if env == nil {
+		return OSEnvironment{}
+	}
+	return env
+}
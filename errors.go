@@ -10,4 +10,52 @@ var (
 	ErrInternal = errors.New("internal error")
 	// ErrMissingArgs is an internal error caused by passing insufficient parameters to a variadic method.
 	ErrMissingArgs = fmt.Errorf("%w: missing arguments", ErrInternal)
+	// ErrNoPieces is returned when asked to form a path from zero APieces.
+	ErrNoPieces = fmt.Errorf("%w: at least one APiece is required", ErrMissingArgs)
+	// ErrNotAbsolute is returned when an absolute path was required but a relative one was given.
+	ErrNotAbsolute = errors.New("path is not absolute")
+	// ErrUnknownAPathType is returned when parsing an APathType name we don't recognize.
+	ErrUnknownAPathType = errors.New("unknown APathType")
+	// ErrBadEncoding is returned when decoding malformed binary APath data.
+	ErrBadEncoding = errors.New("malformed APath encoding")
+	// ErrCannotScan is returned when a database value can't be converted to an apathy type.
+	ErrCannotScan = errors.New("cannot scan value")
+	// ErrNotDir is returned when a directory was required but the path was something else.
+	ErrNotDir = errors.New("not a directory")
+	// ErrNotFile is returned when a regular file was required but the path was something else.
+	ErrNotFile = errors.New("not a regular file")
+	// ErrNotFound is returned when LookPath can't find an executable.
+	ErrNotFound = errors.New("executable file not found")
+	// ErrUndefinedVariable is returned by Expand when a referenced variable isn't set.
+	ErrUndefinedVariable = errors.New("undefined variable")
+	// ErrBadSyntax is returned by Expand for malformed references such as "${VAR".
+	ErrBadSyntax = errors.New("bad syntax")
+	// ErrEscapesRoot is returned by SecureJoin when a path would leave the root.
+	ErrEscapesRoot = errors.New("path escapes root")
+	// ErrUnsafePath is returned by SecureJoin for absolute paths, drive letters, UNC
+	// prefixes and NUL bytes.
+	ErrUnsafePath = errors.New("unsafe path")
+	// ErrTooManyLinks is returned when resolving symlinks appears to be going in circles.
+	ErrTooManyLinks = errors.New("too many levels of symbolic links")
+	// ErrNotUTF8 is returned when marshaling an APiece that isn't valid UTF-8 as text.
+	ErrNotUTF8 = errors.New("not valid UTF-8")
+	// ErrUnknownPlatform is returned for a Platform, or Platform name, we don't recognize.
+	ErrUnknownPlatform = errors.New("unknown platform")
 )
+
+// PathError records an error and the operation and APiece that caused it. It
+// works with errors.Is/errors.As in the same manner as fs.PathError.
+type PathError struct {
+	Op    string
+	Piece APiece
+	Err   error
+}
+
+func (e *PathError) Error() string {
+	return e.Op + " " + e.Piece.String() + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *PathError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,149 @@
+package apathy
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ExpandSyntax selects which kinds of reference Expand will replace.
+type ExpandSyntax uint8
+
+const (
+	// ExpandTilde replaces a leading ~ or ~user with a home directory.
+	ExpandTilde ExpandSyntax = 1 << iota
+	// ExpandDollar replaces $VAR and ${VAR}; $$ gives a literal $.
+	ExpandDollar
+	// ExpandPercent replaces %VAR%; %% gives a literal %.
+	ExpandPercent
+
+	// ExpandAll enables every syntax.
+	ExpandAll = ExpandTilde | ExpandDollar | ExpandPercent
+)
+
+// Expand replaces ~, ~user, $VAR, ${VAR} and %VAR% references in str using env,
+// and returns the result as a clean APiece. See ExpandWith.
+func Expand(str string, env Environment) (APiece, error) {
+	return ExpandWith(str, env, ExpandAll)
+}
+
+// ExpandWith is Expand restricted to the given syntaxes; anything else is left as it
+// is. Referencing an undefined variable is an error rather than silently producing
+// a different path. A nil env means OSEnvironment.
+//
+// Backslashes are path separators as far as we're concerned, so they don't escape
+// anything; use $$ or %% for a literal $ or %. A % is also literal unless it starts
+// a %NAME% whose name is a valid identifier, allowing the parentheses of names like
+// ProgramFiles(x86), so "50% of 100%" is left alone.
+func ExpandWith(str string, env Environment, syntax ExpandSyntax) (APiece, error) {
+	env = envOrOS(env)
+	var buf strings.Builder
+	buf.Grow(len(str))
+
+	idx := 0
+	if syntax&ExpandTilde != 0 && strings.HasPrefix(str, "~") {
+		end := strings.IndexAny(str, `/\`)
+		if end < 0 {
+			end = len(str)
+		}
+		home, err := env.HomeDir(str[1:end])
+		if err != nil {
+			return "", &PathError{Op: "Expand", Piece: APiece(str[:end]), Err: err}
+		}
+		buf.WriteString(home)
+		idx = end
+	}
+
+	for idx < len(str) {
+		c := str[idx]
+		switch {
+		case c == '$' && syntax&ExpandDollar != 0:
+			name, consumed, err := parseDollarRef(str[idx:])
+			if err != nil {
+				return "", err
+			}
+			if err := expandRef(&buf, env, name, str[idx:idx+consumed]); err != nil {
+				return "", err
+			}
+			idx += consumed
+
+		case c == '%' && syntax&ExpandPercent != 0:
+			end := strings.IndexByte(str[idx+1:], '%')
+			if end < 0 || !isPercentName(str[idx+1:idx+1+end]) {
+				// Lone %, e.g. "100%", or just text between two of them.
+				buf.WriteByte(c)
+				idx++
+				break
+			}
+			name := str[idx+1 : idx+1+end]
+			if err := expandRef(&buf, env, name, str[idx:idx+end+2]); err != nil {
+				return "", err
+			}
+			idx += end + 2
+
+		default:
+			buf.WriteByte(c)
+			idx++
+		}
+	}
+	return NewAPiece(buf.String()), nil
+}
+
+// parseDollarRef parses a $ reference at the start of str, returning the variable
+// name (empty for a literal $) and how many bytes the reference used.
+func parseDollarRef(str string) (name string, consumed int, err error) {
+	if len(str) == 1 {
+		return "", 1, nil
+	}
+	if str[1] == '$' {
+		return "", 2, nil
+	}
+	if str[1] == '{' {
+		end := strings.IndexByte(str, '}')
+		if end < 0 {
+			return "", 0, fmt.Errorf("%w: missing } in %q", ErrBadSyntax, str)
+		}
+		if end == 2 {
+			return "", 0, fmt.Errorf("%w: empty ${}", ErrBadSyntax)
+		}
+		return str[2:end], end + 1, nil
+	}
+	end := 1
+	for end < len(str) && isVarNameByte(str[end], end == 1) {
+		end++
+	}
+	if end == 1 {
+		// Not a reference, e.g. "$5" or "cost$".
+		return "", 1, nil
+	}
+	return str[1:end], end, nil
+}
+
+func isVarNameByte(c byte, first bool) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (!first && c >= '0' && c <= '9')
+}
+
+// isPercentName reports whether name can be the NAME in %NAME%: empty, for %%, or
+// an identifier that may also contain parentheses.
+func isPercentName(name string) bool {
+	for idx := 0; idx < len(name); idx++ {
+		if c := name[idx]; !isVarNameByte(c, idx == 0) && c != '(' && c != ')' {
+			return false
+		}
+	}
+	return true
+}
+
+// expandRef writes the value of the named variable, or, for an empty name, the
+// escaped character that the reference stood for.
+func expandRef(buf *strings.Builder, env Environment, name, ref string) error {
+	if name == "" {
+		buf.WriteByte(ref[0])
+		return nil
+	}
+	value, ok := env.LookupEnv(name)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUndefinedVariable, ref)
+	}
+	buf.WriteString(value)
+	return nil
+}
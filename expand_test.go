@@ -0,0 +1,134 @@
+package apathy
+
+import (
+	"os/user"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testEnv = MapEnvironment{
+	Vars: map[string]string{
+		"HOME":              "/home/artist",
+		"ASSETS":            `D:\assets\`,
+		"ProgramFiles(x86)": `C:\Program Files (x86)`,
+		"EMPTY":             "",
+		"_under_score9":     "u",
+	},
+	Homes: map[string]string{"builder": "/var/lib/builder"},
+}
+
+func TestExpand(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct{ input, expected string }{
+		{"", "."},
+		{"plain/path", "plain/path"},
+		{"~", "/home/artist"},
+		{"~/cache", "/home/artist/cache"},
+		{`~\cache`, "/home/artist/cache"},
+		{"~builder/out", "/var/lib/builder/out"},
+		{"a/~/b", "a/~/b"},
+		{"$ASSETS/textures", "D:/assets/textures"},
+		{"${ASSETS}textures", "D:/assets/textures"},
+		{"%ASSETS%/textures", "D:/assets/textures"},
+		{"%ProgramFiles(x86)%/Tool", "C:/Program Files (x86)/Tool"},
+		{"$HOME/$EMPTY/x", "/home/artist/x"},
+		{"$_under_score9.txt", "u.txt"},
+		{"cost$$/100%%", "cost$/100%"},
+		{"100%", "100%"},
+		{"50% of 100%", "50% of 100%"},
+		{"50% of %ASSETS%", "50% of D:/assets"},
+		{"$5 $", "$5 $"},
+		{"~/%ASSETS%/${HOME}", "/home/artist/D:/assets/home/artist"},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			piece, err := Expand(tc.input, testEnv)
+			require.NoError(t, err)
+			assert.Equal(t, APiece(tc.expected), piece)
+		})
+	}
+}
+
+func TestExpandWith_Syntax(t *testing.T) {
+	t.Parallel()
+
+	const input = "~/$HOME/%HOME%"
+	for _, tc := range []struct {
+		syntax   ExpandSyntax
+		expected string
+	}{
+		{0, "~/$HOME/%HOME%"},
+		{ExpandTilde, "/home/artist/$HOME/%HOME%"},
+		{ExpandDollar, "~/home/artist/%HOME%"},
+		{ExpandPercent, "~/$HOME/home/artist"},
+		{ExpandAll, "/home/artist/home/artist/home/artist"},
+	} {
+		piece, err := ExpandWith(input, testEnv, tc.syntax)
+		require.NoError(t, err)
+		assert.Equal(t, APiece(tc.expected), piece)
+	}
+}
+
+func TestExpand_Errors(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		input string
+		err   error
+	}{
+		{"$NOPE/x", ErrUndefinedVariable},
+		{"${NOPE}", ErrUndefinedVariable},
+		{"%NOPE%", ErrUndefinedVariable},
+		{"${HOME", ErrBadSyntax},
+		{"${}", ErrBadSyntax},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			_, err := Expand(tc.input, testEnv)
+			assert.ErrorIs(t, err, tc.err)
+		})
+	}
+
+	_, err := Expand("~nobody/x", testEnv)
+	assert.ErrorAs(t, err, new(user.UnknownUserError))
+	var pathErr *PathError
+	assert.ErrorAs(t, err, &pathErr)
+	assert.Equal(t, APiece("~nobody"), pathErr.Piece)
+
+	_, err = Expand("~", MapEnvironment{})
+	assert.Error(t, err)
+}
+
+func TestMapEnvironment_HomeDir(t *testing.T) {
+	t.Parallel()
+
+	home, err := MapEnvironment{Vars: map[string]string{"USERPROFILE": `C:\Users\artist`}}.HomeDir("")
+	assert.NoError(t, err)
+	assert.Equal(t, `C:\Users\artist`, home)
+}
+
+func TestOSEnvironment(t *testing.T) {
+	// Can't be parallel because it modifies globals.
+	defer withSaved(&LookupEnv, func(key string) (string, bool) {
+		return "value-of-" + key, true
+	})()
+	defer withSaved(&UserHomeDir, func() (string, error) {
+		return "/home/me", nil
+	})()
+	defer withSaved(&LookupUser, func(name string) (*user.User, error) {
+		if name == "them" {
+			return &user.User{Username: name, HomeDir: "/home/them"}, nil
+		}
+		return nil, user.UnknownUserError(name)
+	})()
+
+	piece, err := Expand("~/$X", nil)
+	require.NoError(t, err)
+	assert.Equal(t, APiece("/home/me/value-of-X"), piece)
+	piece, err = Expand("~them", OSEnvironment{})
+	require.NoError(t, err)
+	assert.Equal(t, APiece("/home/them"), piece)
+	_, err = Expand("~nobody", nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,49 @@
+package apathy
+
+import "io/fs"
+
+// FindUp looks for any of the markers (e.g. ".git", "go.mod") in start and then each
+// of its parents in turn, returning the APath of the first marker that exists. If
+// start is a file, the search begins in its directory. Markers may be relative
+// paths like ".config/project.yaml", and are tried in the order given.
+//
+// The search stops at the root of the volume: "/", a drive root such as "C:/",
+// or on Windows a UNC share root such as "//server/share". Directories that can't be read
+// are skipped. If no marker is found, the error wraps fs.ErrNotExist.
+func FindUp(start APath, markers ...APiece) (APath, error) {
+	if len(markers) == 0 {
+		return nil, ErrNoPieces
+	}
+	dir := start.Piece()
+	if start.Exists() && !start.IsDir() {
+		dir = Dir(dir)
+	}
+	for {
+		for _, marker := range markers {
+			// An unreadable directory on the way up shouldn't stop the search.
+			found, err := NewAPathTolerant(dir, marker)
+			if err != nil {
+				return nil, err
+			}
+			if found.Exists() {
+				return found, nil
+			}
+		}
+		parent := Dir(dir)
+		if isVolumeRoot(dir) || parent == dir || parent.Len() >= dir.Len() {
+			break
+		}
+		dir = parent
+	}
+	return nil, &PathError{Op: "FindUp", Piece: start.Piece(), Err: fs.ErrNotExist}
+}
+
+// isVolumeRoot returns true for "/", drive roots ("C:/" or "C:"), and on Windows
+// UNC share roots ("//server/share").
+func isVolumeRoot(p APiece) bool {
+	if p == "/" || (hasDriveLetter(p) && p.Len() <= WindowsDriveRootLen) {
+		return true
+	}
+	share := hostUNCRoot(p)
+	return share != "" && share == p
+}
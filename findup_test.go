@@ -0,0 +1,109 @@
+package apathy
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindUp(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	deep := filepath.Join(root, "project", "src", "pkg")
+	require.NoError(t, os.MkdirAll(deep, 0755))
+	require.NoError(t, os.Mkdir(filepath.Join(root, "project", ".git"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "project", "src", "go.mod"), nil, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(deep, "main.go"), nil, 0644))
+	rootPiece := NewAPiece(root)
+
+	start := MustNewAPath(NewAPiece(deep))
+	found, err := FindUp(start, ".git")
+	require.NoError(t, err)
+	assert.Equal(t, Join(rootPiece, "project/.git"), found.Piece())
+	assert.True(t, found.IsDir())
+
+	// Markers are tried in order at each level before moving up.
+	found, err = FindUp(start, ".git", "go.mod")
+	require.NoError(t, err)
+	assert.Equal(t, Join(rootPiece, "project/src/go.mod"), found.Piece())
+
+	// Starting from a file searches from its directory.
+	found, err = FindUp(MustNewAPath(NewAPiece(deep), "main.go"), "main.go")
+	require.NoError(t, err)
+	assert.Equal(t, Join(rootPiece, "project/src/pkg/main.go"), found.Piece())
+
+	// Marker paths can have more than one component.
+	found, err = FindUp(start, "src/go.mod")
+	require.NoError(t, err)
+	assert.Equal(t, Join(rootPiece, "project/src/go.mod"), found.Piece())
+
+	_, err = FindUp(start, "no-such-marker-anywhere.yaml")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	_, err = FindUp(start)
+	assert.ErrorIs(t, err, ErrNoPieces)
+}
+
+func TestFindUp_Roots(t *testing.T) {
+	// Can't be parallel because it modifies globals. Pretend nothing exists, so
+	// we have to climb all the way up, and make sure we stop.
+	var visited []string
+	defer withSaved(&Lstat, func(path string) (os.FileInfo, error) {
+		visited = append(visited, path)
+		return nil, os.ErrNotExist
+	})()
+	defer withSaved(&Abs, func(path string) (string, error) {
+		return path, nil
+	})()
+
+	for _, tc := range []struct {
+		start   APiece
+		visited []string
+	}{
+		{"/a/b/c", []string{"/a/b/c/.git", "/a/b/.git", "/a/.git", "/.git"}},
+		{"/", []string{"/.git"}},
+		{"C:/Windows/System32", []string{"C:/Windows/System32/.git", "C:/Windows/.git", "C:/.git"}},
+		{"c:/", []string{"c:/.git"}},
+	} {
+		t.Run(tc.start.String(), func(t *testing.T) {
+			visited = nil
+			_, err := FindUp(&aPath{APiece: tc.start}, ".git")
+			assert.ErrorIs(t, err, fs.ErrNotExist)
+			assert.Equal(t, tc.visited, visited)
+		})
+	}
+
+	// Only Windows has UNC share roots to stop at.
+	if onWindows {
+		visited = nil
+		_, err := FindUp(&aPath{APiece: "//server/share/dir"}, ".git")
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+		assert.Equal(t, []string{"//server/share/dir/.git", "//server/share/.git"}, visited)
+	}
+}
+
+func Test_isVolumeRoot(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		piece    APiece
+		expected bool
+	}{
+		{"/", true},
+		{"C:/", true},
+		{"z:", true},
+		{"//server/share", onWindows},
+		{"/etc", false},
+		{"C:/Windows", false},
+		{"//server", false},
+		{"//server/", false},
+		{"//server/share/dir", false},
+		{".", false},
+	} {
+		assert.Equal(t, tc.expected, isVolumeRoot(tc.piece), tc.piece.String())
+	}
+}
@@ -0,0 +1,146 @@
+package apathy
+
+import (
+	"io/fs"
+	"strings"
+)
+
+// FlagRequirement constrains the values a PathFlag or PathListFlag will accept.
+// MustExist is checked against the Lstat of the path, so a dangling symlink exists,
+// while MustBeDir and MustBeFile check what a symlink points to.
+type FlagRequirement uint8
+
+const (
+	// MustExist rejects paths that don't exist.
+	MustExist FlagRequirement = 1 << iota
+	// MustBeDir rejects paths that aren't directories, including ones that don't exist.
+	// A symlink to a directory is accepted.
+	MustBeDir
+	// MustBeFile rejects paths that aren't regular files, including ones that don't exist.
+	// A symlink to a regular file is accepted.
+	MustBeFile
+)
+
+// PieceFlag is a flag.Value that cleans its argument into an APiece. It does not
+// resolve or Lstat the value; use PathFlag for that.
+//
+//	var name apathy.PieceFlag
+//	flag.Var(&name, "name", "asset name")
+type PieceFlag struct {
+	Piece APiece
+}
+
+func (f *PieceFlag) String() string {
+	return f.Piece.String()
+}
+
+// Set implements flag.Value.
+func (f *PieceFlag) Set(value string) error {
+	f.Piece = NewAPiece(value)
+	return nil
+}
+
+// PathFlag is a flag.Value that resolves its argument into an APath, optionally
+// relative to Base, and checks it against Require.
+//
+//	src := apathy.PathFlag{Require: apathy.MustBeDir}
+//	flag.Var(&src, "src", "source folder")
+//	flag.Parse()
+//	... src.Path ...
+type PathFlag struct {
+	// Path is the parsed value, or nil if the flag was not given.
+	Path APath
+	// Base is what relative values are resolved against; empty means the working directory.
+	Base APiece
+	// Require lists the checks the value must pass.
+	Require FlagRequirement
+}
+
+func (f *PathFlag) String() string {
+	if f == nil || f.Path == nil {
+		return ""
+	}
+	return f.Path.String()
+}
+
+// Set implements flag.Value.
+func (f *PathFlag) Set(value string) error {
+	p, err := resolveFlagValue(value, f.Base, f.Require)
+	if err != nil {
+		return err
+	}
+	f.Path = p
+	return nil
+}
+
+// PathListFlag is a repeatable PathFlag: each occurrence of the flag appends to Paths.
+//
+//	var inputs apathy.PathListFlag
+//	flag.Var(&inputs, "in", "input file (repeatable)")
+type PathListFlag struct {
+	// Paths holds the parsed values in the order they were given.
+	Paths []APath
+	// Base is what relative values are resolved against; empty means the working directory.
+	Base APiece
+	// Require lists the checks each value must pass.
+	Require FlagRequirement
+}
+
+func (f *PathListFlag) String() string {
+	if f == nil {
+		return ""
+	}
+	strs := make([]string, len(f.Paths))
+	for idx, p := range f.Paths {
+		strs[idx] = p.String()
+	}
+	return strings.Join(strs, ",")
+}
+
+// Set implements flag.Value.
+func (f *PathListFlag) Set(value string) error {
+	p, err := resolveFlagValue(value, f.Base, f.Require)
+	if err != nil {
+		return err
+	}
+	f.Paths = append(f.Paths, p)
+	return nil
+}
+
+func resolveFlagValue(value string, base APiece, require FlagRequirement) (APath, error) {
+	piece := NewAPiece(value)
+	pieces := []APiece{piece}
+	if base != "" && !piece.IsAbs() {
+		pieces = []APiece{base, piece}
+	}
+	p, err := NewAPath(pieces...)
+	if err != nil {
+		return nil, err
+	}
+
+	// The APath describes a symlink itself, but the requirement is about what the
+	// user is pointing us at.
+	isDir, isFile := p.IsDir(), p.IsFile()
+	if require&(MustBeDir|MustBeFile) != 0 && p.Type() == ATypeSymlink {
+		if info, err := Stat(Normalize(p.Piece())); err == nil {
+			isDir, isFile = info.IsDir(), info.Mode().IsRegular()
+		}
+	}
+
+	var failure error
+	switch {
+	case require&MustBeDir != 0 && !isDir:
+		failure = ErrNotDir
+	case require&MustBeFile != 0 && !isFile:
+		failure = ErrNotFile
+	case require&MustExist != 0 && !p.Exists():
+		failure = fs.ErrNotExist
+	}
+	if failure != nil {
+		if !p.Exists() {
+			failure = fs.ErrNotExist
+		}
+		return nil, &PathError{Op: "flag", Piece: p.Piece(), Err: failure}
+	}
+	return p, nil
+}
@@ -0,0 +1,130 @@
+package apathy
+
+import (
+	"flag"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestFlagSet() *flag.FlagSet {
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	return flags
+}
+
+func TestPieceFlag(t *testing.T) {
+	t.Parallel()
+
+	var name PieceFlag
+	flags := newTestFlagSet()
+	flags.Var(&name, "name", "")
+	require.NoError(t, flags.Parse([]string{"-name", `textures\..\models\hero.fbx`}))
+	assert.Equal(t, APiece("models/hero.fbx"), name.Piece)
+	assert.Equal(t, "models/hero.fbx", name.String())
+}
+
+func TestPathFlag(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file"), nil, 0644))
+	base := NewAPiece(dir)
+
+	for _, tc := range []struct {
+		name    string
+		value   string
+		require FlagRequirement
+		want    APiece
+		wantErr error
+	}{
+		{"relative", "file", 0, Join(base, "file"), nil},
+		{"absolute ignores base", "/elsewhere", 0, "/elsewhere", nil},
+		{"missing ok", "missing", 0, Join(base, "missing"), nil},
+		{"must exist", "file", MustExist, Join(base, "file"), nil},
+		{"must exist missing", "missing", MustExist, "", fs.ErrNotExist},
+		{"must be file", "file", MustBeFile, Join(base, "file"), nil},
+		{"must be file dir", ".", MustBeFile, "", ErrNotFile},
+		{"must be file missing", "missing", MustBeFile, "", fs.ErrNotExist},
+		{"must be dir", ".", MustBeDir, base, nil},
+		{"must be dir file", "file", MustBeDir, "", ErrNotDir},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if onWindows && tc.value == "/elsewhere" {
+				t.Skip("posix root isn't absolute enough for windows")
+			}
+			// The flag package doesn't wrap the errors from Set, so call it directly.
+			pathFlag := PathFlag{Base: base, Require: tc.require}
+			err := pathFlag.Set(tc.value)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				assert.Nil(t, pathFlag.Path)
+				assert.Equal(t, "", pathFlag.String())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, pathFlag.Path.Piece())
+			assert.Equal(t, tc.want.String(), pathFlag.String())
+		})
+	}
+
+	src := PathFlag{Base: base, Require: MustBeDir}
+	flags := newTestFlagSet()
+	flags.Var(&src, "src", "")
+	require.NoError(t, flags.Parse([]string{"-src", "."}))
+	assert.Equal(t, base, src.Path.Piece())
+	assert.Error(t, flags.Parse([]string{"-src", "file"}))
+}
+
+func TestPathFlag_Symlink(t *testing.T) {
+	t.Parallel()
+	if onWindows {
+		t.Skip("symlinks need privileges on windows")
+	}
+
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "real"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file"), nil, 0644))
+	require.NoError(t, os.Symlink("real", filepath.Join(dir, "linkdir")))
+	require.NoError(t, os.Symlink("file", filepath.Join(dir, "linkfile")))
+	require.NoError(t, os.Symlink("nowhere", filepath.Join(dir, "dangling")))
+	base := NewAPiece(dir)
+
+	// The requirement applies to the target, but the path is the one given.
+	dirFlag := PathFlag{Base: base, Require: MustBeDir}
+	require.NoError(t, dirFlag.Set("linkdir"))
+	assert.Equal(t, Join(base, "linkdir"), dirFlag.Path.Piece())
+	assert.ErrorIs(t, dirFlag.Set("linkfile"), ErrNotDir)
+	assert.ErrorIs(t, dirFlag.Set("dangling"), ErrNotDir)
+
+	fileFlag := PathFlag{Base: base, Require: MustBeFile}
+	require.NoError(t, fileFlag.Set("linkfile"))
+	assert.Equal(t, Join(base, "linkfile"), fileFlag.Path.Piece())
+	assert.ErrorIs(t, fileFlag.Set("linkdir"), ErrNotFile)
+}
+
+func TestPathListFlag(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a"), nil, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b"), nil, 0644))
+	base := NewAPiece(dir)
+
+	inputs := PathListFlag{Base: base, Require: MustBeFile}
+	flags := newTestFlagSet()
+	flags.Var(&inputs, "in", "")
+	require.NoError(t, flags.Parse([]string{"-in", "b", "-in", "a"}))
+	require.Len(t, inputs.Paths, 2)
+	assert.Equal(t, Join(base, "b"), inputs.Paths[0].Piece())
+	assert.Equal(t, Join(base, "a"), inputs.Paths[1].Piece())
+	assert.Equal(t, Join(base, "b").String()+","+Join(base, "a").String(), inputs.String())
+
+	assert.ErrorIs(t, inputs.Set("c"), fs.ErrNotExist)
+	assert.Len(t, inputs.Paths, 2)
+}
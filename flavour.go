@@ -0,0 +1,25 @@
+package apathy
+
+// Flavour selects which platform's conventions apply to a lexical operation, so
+// that, e.g., Windows-style lists can be handled on a Linux build machine.
+type Flavour uint8
+
+const (
+	FlavourPosix   Flavour = iota // FlavourPosix follows unix conventions, e.g. ':'-separated PATH.
+	FlavourWindows                // FlavourWindows follows Windows conventions, e.g. ';'-separated PATH.
+)
+
+func (f Flavour) String() string {
+	if f == FlavourWindows {
+		return "windows"
+	}
+	return "posix"
+}
+
+// ListSeparator returns the separator used in PATH-style lists.
+func (f Flavour) ListSeparator() byte {
+	if f == FlavourWindows {
+		return ';'
+	}
+	return ':'
+}
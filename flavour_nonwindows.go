@@ -0,0 +1,6 @@
+//go:build !windows
+
+package apathy
+
+// NativeFlavour is the Flavour of the platform we're running on.
+const NativeFlavour = FlavourPosix
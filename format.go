@@ -0,0 +1,97 @@
+package apathy
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"unicode/utf8"
+)
+
+// Format implements fmt.Formatter, so that log lines agree on which separators
+// they show. In addition to the usual string verbs and flags, which all operate
+// on the posix form:
+//
+//	%s, %v  the posix form: c:/windows/notepad.exe
+//	%q      the posix form, quoted: "c:/windows/notepad.exe"
+//	%n      the Normalize()d form: c:\windows\notepad.exe
+//
+// Since APath implementations embed or wrap an APiece, this generally applies to
+// them too.
+func (p APiece) Format(f fmt.State, verb rune) {
+	str := string(p)
+	if verb == 'n' {
+		str, verb = p.Normalize(), 's'
+	}
+	fmt.Fprintf(f, fmt.FormatString(f, verb), str)
+}
+
+// LogValue implements slog.LogValuer, grouping the path with the type, size and
+// mtime from the last Lstat, or the error if it was AInaccessible.
+func (p *aPath) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("path", EscapeDisplay(p)),
+		slog.String("type", p.aType.String()),
+	}
+	if p.Exists() {
+		attrs = append(attrs, slog.Int64("size", p.size), slog.Time("mtime", p.mtime))
+	}
+	if p.err != nil {
+		attrs = append(attrs, slog.String("error", p.err.Error()))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// EscapeDisplay makes a piece safe to print or log: bytes that aren't valid UTF-8,
+// and control characters, become \xNN, and backslashes become \\. Everything else,
+// including valid non-ASCII names, is shown as is. UnescapeDisplay reverses it, so
+// no information is lost.
+func EscapeDisplay(piece Piecer) string {
+	str := piece.Piece().String()
+	var buf strings.Builder
+	buf.Grow(len(str))
+	for idx := 0; idx < len(str); {
+		r, size := utf8.DecodeRuneInString(str[idx:])
+		switch {
+		case r == utf8.RuneError && size == 1, r < 0x20, r == 0x7f:
+			fmt.Fprintf(&buf, `\x%02x`, str[idx])
+		case r == '\\':
+			buf.WriteString(`\\`)
+		default:
+			buf.WriteString(str[idx : idx+size])
+		}
+		idx += size
+	}
+	return buf.String()
+}
+
+// UnescapeDisplay reverses EscapeDisplay, and cleans the result via NewAPiece. A
+// backslash that doesn't start \xNN or \\ is an ErrBadSyntax.
+func UnescapeDisplay(str string) (APiece, error) {
+	if !strings.Contains(str, `\`) {
+		return NewAPiece(str), nil
+	}
+	var buf strings.Builder
+	buf.Grow(len(str))
+	for idx := 0; idx < len(str); idx++ {
+		if str[idx] != '\\' {
+			buf.WriteByte(str[idx])
+			continue
+		}
+		if idx+1 < len(str) && str[idx+1] == '\\' {
+			buf.WriteByte('\\')
+			idx++
+			continue
+		}
+		if idx+3 >= len(str) || str[idx+1] != 'x' {
+			return "", fmt.Errorf("%w: bad escape at offset %d of %q", ErrBadSyntax, idx, str)
+		}
+		hi, okHi := unhex(str[idx+2])
+		lo, okLo := unhex(str[idx+3])
+		if !okHi || !okLo {
+			return "", fmt.Errorf("%w: bad escape %q", ErrBadSyntax, str[idx:idx+4])
+		}
+		buf.WriteByte(hi<<4 | lo)
+		idx += 3
+	}
+	return NewAPiece(buf.String()), nil
+}
@@ -0,0 +1,103 @@
+package apathy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPiece_Format(t *testing.T) {
+	t.Parallel()
+
+	piece := APiece("c:/windows/notepad.exe")
+	for _, tc := range []struct{ format, expected string }{
+		{"%s", "c:/windows/notepad.exe"},
+		{"%v", "c:/windows/notepad.exe"},
+		{"%q", `"c:/windows/notepad.exe"`},
+		{"%n", `c:\windows\notepad.exe`},
+		{"%25s|", "   c:/windows/notepad.exe|"},
+		{"%-25n|", `c:\windows\notepad.exe   |`},
+		{"%.2s", "c:"},
+		{"%x", "633a2f"},
+	} {
+		t.Run(tc.format, func(t *testing.T) {
+			subject := piece
+			if tc.format == "%x" {
+				subject = "c:/"
+			}
+			assert.Equal(t, tc.expected, fmt.Sprintf(tc.format, subject))
+		})
+	}
+
+	// APaths pick this up from the APiece they embed.
+	var apath APath = &aPath{APiece: "c:/temp", aType: ATypeDir}
+	assert.Equal(t, `c:/temp c:\temp "c:/temp"`, fmt.Sprintf("%s %n %q", apath, apath, apath))
+}
+
+func TestAPath_LogValue(t *testing.T) {
+	t.Parallel()
+
+	mtime := time.Date(2025, 2, 5, 12, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		name     string
+		apath    APath
+		expected string
+	}{
+		{"file", &aPath{APiece: "/a/b", aType: ATypeFile, size: 10, mtime: mtime},
+			`msg=hi p.path=/a/b p.type=File p.size=10 p.mtime=2025-02-05T12:00:00.000Z`},
+		{"missing", &aPath{APiece: "/gone"},
+			`msg=hi p.path=/gone p.type=NotExist`},
+		{"inaccessible", &aPath{APiece: "/secret", aType: AInaccessible, err: errors.New("denied")},
+			`msg=hi p.path=/secret p.type=Inaccessible p.error=denied`},
+		// Paths are escaped, so log lines stay valid UTF-8.
+		{"not-utf8", &aPath{APiece: "/caf\xe9"},
+			`msg=hi p.path=/caf\xe9 p.type=NotExist`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+				ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+					if len(groups) == 0 && (attr.Key == slog.TimeKey || attr.Key == slog.LevelKey) {
+						return slog.Attr{}
+					}
+					return attr
+				},
+			}))
+			logger.Info("hi", "p", tc.apath)
+			assert.Equal(t, tc.expected+"\n", buf.String())
+		})
+	}
+}
+
+func TestEscapeDisplay(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct{ name, piece, expected string }{
+		{"plain", "/srv/assets/a.png", "/srv/assets/a.png"},
+		{"unicode", "/srv/caf\u00e9", "/srv/caf\u00e9"},
+		{"latin1", "/srv/caf\xe9", `/srv/caf\xe9`},
+		{"control", "/srv/a\tb\x7f", `/srv/a\x09b\x7f`},
+		{"backslash", `c:\x`, `c:\\x`},
+		{"truncated", "/srv/\xe2\x82", `/srv/\xe2\x82`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			escaped := EscapeDisplay(APiece(tc.piece))
+			assert.Equal(t, tc.expected, escaped)
+			assert.True(t, utf8.ValidString(escaped))
+			unescaped, err := UnescapeDisplay(escaped)
+			assert.NoError(t, err)
+			assert.Equal(t, NewAPiece(tc.piece), unescaped)
+		})
+	}
+
+	for _, bad := range []string{`/a\`, `/a\x4`, `/a\xzz`, `/a\n`} {
+		_, err := UnescapeDisplay(bad)
+		assert.ErrorIs(t, err, ErrBadSyntax, bad)
+	}
+}
@@ -24,7 +24,9 @@ func GetAwd() (APiece, error) {
 }
 
 // Join implements path.Join for one or more path components,
-// into a posix-styled, Clean()d string.
+// into a posix-styled, Clean()d string. On Windows the result only
+// has a UNC prefix if the first piece does, so Join("/", "server",
+// "share") is "/server/share".
 // TODO: We should be able to leverage our knowledge about the state
 // of 'Piece's to implement our own, faster algorithm.
 func Join[T ~string](pieces ...T) APiece {
@@ -47,17 +49,16 @@ func Join[T ~string](pieces ...T) APiece {
 		buf = append(buf, '/')
 		buf = append(buf, str...)
 	}
-	// Use the APiece constructor to ensure the path is clean and posix-styled.
-	return NewAPiece(string(buf))
+	// Use the APiece constructor to ensure the path is clean and posix-styled,
+	// but don't let the separators we added, e.g. after "/", look like UNC.
+	allowUNC := hostUNCRoot(APiece(ToSlash(pieces[0]))) != ""
+	return newAPiece(string(buf), allowUNC)
 }
 
+// ToSlash replaces backslashes with forward slashes. It works on bytes rather than
+// runes, so names that aren't valid UTF-8 come through intact.
 func ToSlash[Str ~string](path Str) string {
-	return strings.Map(func(r rune) rune {
-		if r == '\\' {
-			return '/'
-		}
-		return r
-	}, string(path))
+	return strings.ReplaceAll(string(path), `\`, "/")
 }
 
 func Base(piece Piecer) APiece {
@@ -68,6 +69,14 @@ func Dir(piecer Piecer) APiece {
 	// Windows paths such as 'x:' and 'x:/' need to return 'x:/' as their
 	// path.
 	piece := piecer.Piece()
+	// Windows UNC paths, //server/share/..., can't go above the share, and
+	// path.Dir would collapse the leading double-slash.
+	if share := hostUNCRoot(piece); share != "" {
+		if piece.Len() <= share.Len() {
+			return share
+		}
+		return APiece("/" + path.Dir(piece.String()[1:]))
+	}
 	result := APiece(path.Dir(piece.String()))
 	// If we reach a drive root, check that we don't lose the absolute slash of the parent,
 	// that is, the parent of "c:relative" is "c:", but parent of "c:/absolute" is "c:/".
@@ -39,6 +39,12 @@ func TestJoin(t *testing.T) {
 		{"one empty", []string{""}, "."},
 		{"dot", []string{"."}, "."},
 		{"dot, dot", []string{".", "."}, "."},
+		{"empty first", []string{"", "a"}, "/a"},
+		{"empties", []string{"", "a", "", "b", ""}, "/a/b"},
+		{"empty then root", []string{"", "/etc"}, "/etc"},
+		{"root", []string{"/", "home", "user"}, "/home/user"},
+		{"root then root", []string{"/", "/etc"}, "/etc"},
+		{"not unc later", []string{"/", "//server/share"}, "/server/share"},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			expected := APiece(tc.expecting)
@@ -46,6 +52,25 @@ func TestJoin(t *testing.T) {
 			assert.Equal(t, expected, actual)
 		})
 	}
+
+	// Only Windows keeps a UNC prefix, and then only from the first piece.
+	for _, tc := range []struct {
+		name    string
+		inputs  []string
+		windows APiece
+		posix   APiece
+	}{
+		{"unc", []string{"//server/share", "dir", "../.."}, "//server/share", "/server"},
+		{"unc backslashed", []string{`\\server\share`, "dir"}, "//server/share/dir", "/server/share/dir"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			expected := tc.posix
+			if onWindows {
+				expected = tc.windows
+			}
+			assert.Equal(t, expected, Join(tc.inputs...))
+		})
+	}
 }
 
 func TestNormalize(t *testing.T) {
@@ -62,7 +87,7 @@ func TestNormalize(t *testing.T) {
 func TestAPieceHelpers(t *testing.T) {
 	t.Parallel()
 
-	a := &aPath{"/usr/lib/postgres/fire.theres_actual_fire", ANotExist, fixedTime, 0}
+	a := &aPath{APiece: "/usr/lib/postgres/fire.theres_actual_fire", aType: ANotExist, mtime: fixedTime}
 	assert.Equal(t, "fire.theres_actual_fire", Base(a).String())
 	assert.Equal(t, "/usr/lib/postgres", Dir(a).String())
 	assert.Equal(t, ".theres_actual_fire", Ext(a).String())
@@ -93,4 +118,29 @@ func TestAPiece_Dir(t *testing.T) {
 			assert.Equal(t, tc[0], parent)
 		})
 	}
+
+	// Windows can't climb above a UNC share; elsewhere "//" is an ordinary root.
+	for _, tc := range []struct{ piece, windows, posix APiece }{
+		{"//server/share", "//server/share", "/server"},
+		{"//server/share/dir", "//server/share", "/server/share"},
+		{"//server/share/dir/file", "//server/share/dir", "/server/share/dir"},
+		{"//etc/passwd", "//etc/passwd", "/etc"},
+	} {
+		expected := tc.posix
+		if onWindows {
+			expected = tc.windows
+		}
+		assert.Equal(t, expected, Dir(tc.piece), tc.piece.String())
+	}
+
+	// Climbing from something Join built must reach "/" rather than get stuck on
+	// what looks like a UNC share.
+	dirs := []APiece{}
+	for dir := Join("/", "home", "user"); len(dirs) < 5; dir = Dir(dir) {
+		dirs = append(dirs, dir)
+		if dir == "/" {
+			break
+		}
+	}
+	assert.Equal(t, []APiece{"/home/user", "/home", "/"}, dirs)
 }
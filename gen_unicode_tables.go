@@ -0,0 +1,159 @@
+//go:build ignore
+
+// This program generates unicode_tables.go from the Unicode Character Database.
+// Run it with the directory holding UnicodeData.txt and CompositionExclusions.txt:
+//
+//	go run gen_unicode_tables.go -ucd ./ucd -version 17.0.0
+//
+// Use the UCD for unicode.Version, so that normalization matches case folding.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func main() {
+	ucd := flag.String("ucd", ".", "directory containing the UCD files")
+	version := flag.String("version", "", "Unicode version of the UCD files")
+	output := flag.String("output", "unicode_tables.go", "file to write")
+	flag.Parse()
+
+	ccc := map[rune]uint8{}
+	decomp := map[rune][]rune{}
+	forEachLine(filepath.Join(*ucd, "UnicodeData.txt"), func(fields []string) {
+		r := parseRune(fields[0])
+		if class, _ := strconv.Atoi(fields[3]); class != 0 {
+			ccc[r] = uint8(class)
+		}
+		// Compatibility decompositions are tagged "<font>" etc; we only want canonical.
+		if fields[5] != "" && !strings.HasPrefix(fields[5], "<") {
+			for _, cp := range strings.Fields(fields[5]) {
+				decomp[r] = append(decomp[r], parseRune(cp))
+			}
+		}
+	})
+	excluded := map[rune]bool{}
+	forEachLine(filepath.Join(*ucd, "CompositionExclusions.txt"), func(fields []string) {
+		excluded[parseRune(fields[0])] = true
+	})
+
+	// Primary composites are everything with a canonical decomposition, less the
+	// explicit exclusions, singletons, and those starting with a non-starter.
+	compose := map[[2]rune]rune{}
+	for r, d := range decomp {
+		if excluded[r] || len(d) != 2 || ccc[r] != 0 || ccc[d[0]] != 0 {
+			continue
+		}
+		compose[[2]rune{d[0], d[1]}] = r
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by gen_unicode_tables.go from Unicode %s; DO NOT EDIT.\n\n", *version)
+	fmt.Fprintf(&buf, "package apathy\n\n")
+	fmt.Fprintf(&buf, "// unicodeVersion is the version of the tables below.\nconst unicodeVersion = %q\n\n", *version)
+
+	fmt.Fprintf(&buf, "// combiningClasses holds the non-zero canonical combining classes.\n")
+	fmt.Fprintf(&buf, "var combiningClasses = map[rune]uint8{\n")
+	for _, r := range sortedKeys(ccc) {
+		fmt.Fprintf(&buf, "0x%04X: %d,\n", r, ccc[r])
+	}
+	fmt.Fprintf(&buf, "}\n\n")
+
+	fmt.Fprintf(&buf, "// canonicalDecompositions holds full, recursively applied canonical\n")
+	fmt.Fprintf(&buf, "// decompositions, excluding Hangul syllables.\n")
+	fmt.Fprintf(&buf, "var canonicalDecompositions = map[rune]string{\n")
+	for _, r := range sortedKeys(decomp) {
+		fmt.Fprintf(&buf, "0x%04X: %+q,\n", r, string(fullDecomposition(r, decomp)))
+	}
+	fmt.Fprintf(&buf, "}\n\n")
+
+	fmt.Fprintf(&buf, "// canonicalCompositions maps pairs to their primary composite, excluding\n")
+	fmt.Fprintf(&buf, "// Hangul syllables.\n")
+	fmt.Fprintf(&buf, "var canonicalCompositions = map[[2]rune]rune{\n")
+	pairs := make([][2]rune, 0, len(compose))
+	for pair := range compose {
+		pairs = append(pairs, pair)
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i][0] != pairs[j][0] {
+			return pairs[i][0] < pairs[j][0]
+		}
+		return pairs[i][1] < pairs[j][1]
+	})
+	for _, pair := range pairs {
+		fmt.Fprintf(&buf, "{0x%04X, 0x%04X}: 0x%04X,\n", pair[0], pair[1], compose[pair])
+	}
+	fmt.Fprintf(&buf, "}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*output, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func fullDecomposition(r rune, decomp map[rune][]rune) []rune {
+	d, ok := decomp[r]
+	if !ok {
+		return []rune{r}
+	}
+	var full []rune
+	for _, c := range d {
+		full = append(full, fullDecomposition(c, decomp)...)
+	}
+	return full
+}
+
+// forEachLine calls fn with the ';'-separated, trimmed fields of each line that
+// isn't blank or a comment.
+func forEachLine(filename string, fn func(fields []string)) {
+	file, err := os.Open(filename)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		fields := strings.Split(line, ";")
+		for idx := range fields {
+			fields[idx] = strings.TrimSpace(fields[idx])
+		}
+		fn(fields)
+	}
+	if err := scanner.Err(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func parseRune(hex string) rune {
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return rune(value)
+}
+
+func sortedKeys[V any](m map[rune]V) []rune {
+	keys := make([]rune, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
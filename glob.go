@@ -0,0 +1,133 @@
+package apathy
+
+import (
+	"sort"
+	"strings"
+)
+
+// Glob returns the APaths under root whose paths relative to root match the
+// pattern, and none of the excludes; see Pattern for the syntax. A malformed
+// pattern is an ErrBadSyntax. See Pattern.Glob.
+func Glob(root APath, pattern string, excludes ...string) ([]APath, error) {
+	compiled, err := NewPattern(pattern)
+	if err != nil {
+		return nil, err
+	}
+	excluded := make([]*Pattern, len(excludes))
+	for idx, exclude := range excludes {
+		if excluded[idx], err = NewPattern(exclude); err != nil {
+			return nil, err
+		}
+	}
+	return compiled.Glob(root, excluded...)
+}
+
+// Glob walks the tree under root, descending only into directories where the
+// pattern could still match something, and returns the APaths whose paths
+// relative to root match it, sorted by path. Directories are pruned using just
+// the names and types ReadDir provides; only the matches get their DirEntry's
+// Info, which on unix is an Lstat. Symlinks are matched but not followed.
+//
+// An item matching any of the excludes is left out and, if it's a directory, so
+// is everything under it. As with filepath.Glob, directories that can't be read
+// are quietly skipped; the only errors are for root itself.
+func (p *Pattern) Glob(root APath, excludes ...*Pattern) ([]APath, error) {
+	if !root.IsDir() {
+		return nil, &PathError{Op: "Glob", Piece: root.Piece(), Err: ErrNotDir}
+	}
+	base := root.Piece()
+
+	// No need to list directories we can name outright, so long as they aren't
+	// excluded.
+	var matches []APath
+	start := base
+	if prefix := p.literalPrefix(); prefix != "" {
+		for dir := prefix; dir != Dot; dir = Dir(dir) {
+			if matchesAny(excludes, dir) {
+				return nil, nil
+			}
+		}
+		// We have to look at it anyway, to be sure it's a directory and not a link.
+		prefixed, err := NewAPathTolerant(base, prefix)
+		if err != nil {
+			return nil, err
+		}
+		// It can match itself, e.g. "a" for "a/**".
+		if prefixed.Exists() && p.Match(prefix) {
+			matches = append(matches, prefixed)
+		}
+		if !prefixed.IsDir() {
+			return matches, nil
+		}
+		start = prefixed.Piece()
+	}
+
+	if err := p.glob(base, start, excludes, &matches); err != nil {
+		return nil, err
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Piece() < matches[j].Piece() })
+	return matches, nil
+}
+
+// glob adds the matches in dir, and below it, to matches.
+func (p *Pattern) glob(base, dir APiece, excludes []*Pattern, matches *[]APath) error {
+	entries, err := ReadDir(Normalize(dir))
+	if err != nil {
+		// As documented, unreadable directories are skipped.
+		return nil
+	}
+	for _, entry := range entries {
+		item := Join(dir, APiece(entry.Name()))
+		rel, _ := trimBase(base, item)
+		if matchesAny(excludes, rel) {
+			continue
+		}
+		if p.Match(rel) {
+			info, infoErr := entry.Info()
+			match, err := newAPathTolerant("Glob", item, info, infoErr)
+			if err != nil {
+				return err
+			}
+			*matches = append(*matches, match)
+		}
+		// Type() is from the listing, and a symlink isn't a directory.
+		if entry.IsDir() && p.matchesUnder(rel) {
+			if err := p.glob(base, item, excludes, matches); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func matchesAny(patterns []*Pattern, piece APiece) bool {
+	for _, pattern := range patterns {
+		if pattern.Match(piece) {
+			return true
+		}
+	}
+	return false
+}
+
+// literalPrefix returns the leading components that every alternative spells out
+// literally, e.g. "assets/textures" for "assets/textures/{a,b}/*.png". It
+// doesn't include "." or "..", so it stays below the root.
+func (p *Pattern) literalPrefix() APiece {
+	var prefix []string
+	for depth := 0; ; depth++ {
+		var literal string
+		for idx, segments := range p.alternatives {
+			// The last segment names the match itself, not a directory to start in.
+			if depth >= len(segments)-1 {
+				return APiece(strings.Join(prefix, "/"))
+			}
+			segment := segments[depth]
+			if segment.recursive || segment.tokens != nil || segment.literal == "" ||
+				segment.literal == "." || segment.literal == ".." || (idx > 0 && segment.literal != literal) {
+				return APiece(strings.Join(prefix, "/"))
+			}
+			literal = segment.literal
+		}
+		prefix = append(prefix, literal)
+	}
+}
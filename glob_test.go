@@ -0,0 +1,170 @@
+package apathy
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func globbed(t *testing.T, root APath, matches []APath) []APiece {
+	t.Helper()
+	var rels []APiece
+	for _, match := range matches {
+		rel, ok := trimBase(root.Piece(), match.Piece())
+		require.True(t, ok, match.Piece())
+		rels = append(rels, rel)
+	}
+	return rels
+}
+
+func TestGlob(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	makeTree(t, dir,
+		"assets/textures/a.png", "assets/textures/b.jpg", "assets/textures/ui/c.png",
+		"assets/models/car.obj", "assets/models/car.png", "assets/sounds/",
+		"build/out.png", "a.png", "a/b.png",
+	)
+	root := MustNewAPath(NewAPiece(dir))
+
+	for _, tc := range []struct {
+		name     string
+		pattern  string
+		excludes []string
+		expected []APiece
+	}{
+		{"top", "*.png", nil, []APiece{"a.png"}},
+		{"recursive", "**/*.png", nil, []APiece{
+			"a.png", "a/b.png", "assets/models/car.png", "assets/textures/a.png",
+			"assets/textures/ui/c.png", "build/out.png",
+		}},
+		{"excluded", "**/*.png", []string{"build", "assets/textures/**/c.png"}, []APiece{
+			"a.png", "a/b.png", "assets/models/car.png", "assets/textures/a.png",
+		}},
+		{"braces", "assets/{textures,models}/*.{png,obj}", nil, []APiece{
+			"assets/models/car.obj", "assets/models/car.png", "assets/textures/a.png",
+		}},
+		{"dirs", "assets/*", nil, []APiece{"assets/models", "assets/sounds", "assets/textures"}},
+		{"literal", "assets/textures/a.png", nil, []APiece{"assets/textures/a.png"}},
+		{"missing", "nowhere/*.png", nil, nil},
+		{"missing recursive", "nowhere/**", nil, nil},
+		{"prefix matches itself", "assets/textures/**", nil, []APiece{
+			"assets/textures", "assets/textures/a.png", "assets/textures/b.jpg",
+			"assets/textures/ui", "assets/textures/ui/c.png",
+		}},
+		{"excluded prefix", "assets/textures/*", []string{"assets"}, nil},
+		{"parent", "../*", nil, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			matches, err := Glob(root, tc.pattern, tc.excludes...)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, globbed(t, root, matches))
+		})
+	}
+
+	// The APaths come with their information.
+	matches, err := Glob(root, "assets/models/car.*")
+	require.NoError(t, err)
+	require.Len(t, matches, 2)
+	assert.True(t, matches[0].IsFile())
+	assert.EqualValues(t, len("assets/models/car.obj"), matches[0].Size())
+
+	_, err = Glob(root, "{")
+	assert.ErrorIs(t, err, ErrBadSyntax)
+	_, err = Glob(root, "*", "[")
+	assert.ErrorIs(t, err, ErrBadSyntax)
+	_, err = Glob(matches[0], "*")
+	assert.ErrorIs(t, err, ErrNotDir)
+}
+
+// infoCountingEntry counts the calls to Info, since that's an Lstat on unix.
+type infoCountingEntry struct {
+	fs.DirEntry
+	infos *[]string
+}
+
+func (e infoCountingEntry) Info() (fs.FileInfo, error) {
+	*e.infos = append(*e.infos, e.Name())
+	return e.DirEntry.Info()
+}
+
+func TestGlob_Pruning(t *testing.T) {
+	// Can't be parallel because it modifies globals.
+	dir := t.TempDir()
+	makeTree(t, dir, "src/app/main.go", "src/lib/util.go", "docs/x/y/z.md", "assets/a/b/c.png")
+	root := MustNewAPath(NewAPiece(dir))
+
+	var listed []APiece
+	realReadDir := ReadDir
+	defer withSaved(&ReadDir, func(name string) ([]os.DirEntry, error) {
+		rel, _ := trimBase(root.Piece(), NewAPiece(name))
+		listed = append(listed, rel)
+		return realReadDir(name)
+	})()
+
+	// The literal prefix is skipped over, and nothing outside it is listed.
+	matches, err := Glob(root, "src/*/*.go")
+	require.NoError(t, err)
+	assert.Equal(t, []APiece{"src/app/main.go", "src/lib/util.go"}, globbed(t, root, matches))
+	assert.Equal(t, []APiece{"src", "src/app", "src/lib"}, listed)
+
+	// Only directories that can match are listed.
+	listed = nil
+	matches, err = Glob(root, "{docs,src}/*/*.{md,go}")
+	require.NoError(t, err)
+	assert.Equal(t, []APiece{"src/app/main.go", "src/lib/util.go"}, globbed(t, root, matches))
+	assert.Equal(t, []APiece{"", "docs", "docs/x", "src", "src/app", "src/lib"}, listed)
+
+	// Excluded directories aren't listed.
+	listed = nil
+	_, err = Glob(root, "**", "docs", "assets/*")
+	require.NoError(t, err)
+	assert.Equal(t, []APiece{"", "assets", "src", "src/app", "src/lib"}, listed)
+
+	// Only the matches are Lstat()d.
+	var infos []string
+	defer withSaved(&ReadDir, func(name string) ([]os.DirEntry, error) {
+		entries, err := realReadDir(name)
+		for idx, entry := range entries {
+			entries[idx] = infoCountingEntry{entry, &infos}
+		}
+		return entries, err
+	})()
+	matches, err = Glob(root, "**/*.go")
+	require.NoError(t, err)
+	assert.Equal(t, []APiece{"src/app/main.go", "src/lib/util.go"}, globbed(t, root, matches))
+	assert.Equal(t, []string{"main.go", "util.go"}, infos)
+
+	// Unreadable directories are skipped.
+	defer withSaved(&ReadDir, func(name string) ([]os.DirEntry, error) {
+		if filepath.Base(name) == "lib" {
+			return nil, os.ErrPermission
+		}
+		return realReadDir(name)
+	})()
+	matches, err = Glob(root, "src/**/*.go")
+	require.NoError(t, err)
+	assert.Equal(t, []APiece{"src/app/main.go"}, globbed(t, root, matches))
+}
+
+func TestPattern_literalPrefix(t *testing.T) {
+	t.Parallel()
+
+	for pattern, expected := range map[string]APiece{
+		"assets/textures/{a,b}/*.png": "assets/textures",
+		"{assets,src}/x/*":            "",
+		"a/{b/c,b/d}":                 "a/b",
+		"a/b/c.png":                   "a/b",
+		"*.png":                       "",
+		"a/**/b":                      "a",
+		"../a/b":                      "",
+		"/abs/*":                      "",
+	} {
+		assert.Equal(t, expected, MustNewPattern(pattern).literalPrefix(), pattern)
+	}
+}
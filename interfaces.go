@@ -2,7 +2,9 @@
 package apathy
 
 import (
+	"io/fs"
 	"os"
+	"os/user"
 	"path/filepath"
 	"time"
 )
@@ -12,8 +14,12 @@ import (
 // extant item, and if so whether it was either a file, directory, or symlink, and
 // it's size/mtime.
 //
+// APaths built by the ...Tolerant constructors may be AInaccessible, in which case
+// Err() reports why.
+//
 // To refresh the metadata, use see Observe()/ObserveWithInfo.
 type APath interface {
+	Errored
 	Exists
 	IsDir
 	IsFile
@@ -27,6 +33,9 @@ type APath interface {
 	Type() APathType
 }
 
+type Errored interface {
+	Err() error
+}
 type Exists interface {
 	Exists() bool
 }
@@ -58,7 +67,38 @@ type Lengthed interface {
 	Len() int
 }
 
+// Optional metadata. These are captured from the same Lstat as the core APath
+// information, so they cost no extra syscalls, but not every platform can
+// supply them; type-assert an APath to check for them.
+
+// Permissioned is implemented by APaths that know the permission bits of the item.
+type Permissioned interface {
+	Perm() fs.FileMode
+}
+
+// Identified is implemented by APaths that know the device/inode identity of the
+// item. The bool is false when the platform didn't provide one.
+type Identified interface {
+	FileID() (FileID, bool)
+}
+
+// HardLinked is implemented by APaths that know how many hard links the item has.
+type HardLinked interface {
+	Nlink() uint64
+}
+
+// Owned is implemented by APaths that know the owning uid/gid of the item.
+type Owned interface {
+	Owner() (uid, gid int, ok bool)
+}
+
 // For mocking/testing/etc.
 var Abs = filepath.Abs
 var Getwd = os.Getwd
 var Lstat = os.Lstat
+var Stat = os.Stat
+var Readlink = os.Readlink
+var ReadDir = os.ReadDir
+var LookupEnv = os.LookupEnv
+var UserHomeDir = os.UserHomeDir
+var LookupUser = user.Lookup
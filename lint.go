@@ -0,0 +1,230 @@
+package apathy
+
+import (
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+// LintKind classifies the problems Lint reports.
+type LintKind uint8
+
+const (
+	LintViolation        LintKind = iota // A name or path Validate objects to on a target platform.
+	LintCaseCollision                    // Names in one directory that differ only by case.
+	LintUnicodeCollision                 // Names in one directory that differ only by Unicode normalization.
+	LintUnreadable                       // A directory or item that couldn't be examined.
+)
+
+func (k LintKind) String() string {
+	switch k {
+	case LintViolation:
+		return "violation"
+	case LintCaseCollision:
+		return "case collision"
+	case LintUnicodeCollision:
+		return "normalization collision"
+	case LintUnreadable:
+		return "unreadable"
+	default:
+		return "unknown lint"
+	}
+}
+
+// LintOptions says which platforms Lint checks a tree against. The zero value
+// checks against every platform.
+type LintOptions struct {
+	// Platforms are the targets the tree must work on; empty means all of them.
+	Platforms []Platform
+	// WindowsRoot, if set, is where the tree will live on Windows, e.g.
+	// "C:/Users/builder/project", so that path lengths are checked as they will be
+	// there rather than relative to the root.
+	WindowsRoot APiece
+}
+
+func (o LintOptions) platforms() []Platform {
+	if len(o.Platforms) == 0 {
+		return []Platform{PlatformLinux, PlatformDarwin, PlatformWindows}
+	}
+	return o.Platforms
+}
+
+// LintIssue is one problem found by Lint. Paths are relative to the root.
+type LintIssue struct {
+	Kind LintKind
+	Path APiece
+	// Platform and Violation are set for LintViolation.
+	Platform  Platform
+	Violation Violation
+	// Collisions lists every path involved in a case or normalization collision,
+	// including Path.
+	Collisions []APiece
+	// Err is set for LintUnreadable.
+	Err error
+}
+
+func (i LintIssue) String() string {
+	switch i.Kind {
+	case LintViolation:
+		return fmt.Sprintf("%s: %s: %s", EscapeDisplay(i.Path), i.Platform, i.Violation.Error())
+	case LintCaseCollision, LintUnicodeCollision:
+		// Names that only differ in normalization look identical unless escaped.
+		verb := "%q"
+		if i.Kind == LintUnicodeCollision {
+			verb = "%+q"
+		}
+		quoted := make([]string, len(i.Collisions))
+		for idx, collision := range i.Collisions {
+			quoted[idx] = fmt.Sprintf(verb, collision.String())
+		}
+		return fmt.Sprintf("%s: %s between %s", EscapeDisplay(i.Path), i.Kind, strings.Join(quoted, ", "))
+	default:
+		return fmt.Sprintf("%s: %s: %v", EscapeDisplay(i.Path), i.Kind, i.Err)
+	}
+}
+
+// Lint walks the tree under root and reports everything that would stop it from
+// being copied intact to each of the target platforms: names that Validate
+// objects to, names within a directory that collide on a case-insensitive
+// filesystem (Windows, macOS), names that collide once Unicode-normalized (macOS),
+// and anything that can't be read. Issues are reported in walk order, with
+// collisions reported against the first name involved.
+//
+// The error is only for problems with root itself, or a platform in opts that
+// isn't known, which wraps ErrUnknownPlatform.
+func Lint(root APath, opts LintOptions) ([]LintIssue, error) {
+	if !root.Exists() {
+		err := root.Err()
+		if err == nil {
+			err = fs.ErrNotExist
+		}
+		return nil, &PathError{Op: "Lint", Piece: root.Piece(), Err: err}
+	}
+	if !root.IsDir() {
+		return nil, &PathError{Op: "Lint", Piece: root.Piece(), Err: ErrNotDir}
+	}
+
+	platforms := opts.platforms()
+	var foldCase, unicodeNormalized bool
+	for _, platform := range platforms {
+		if _, err := platform.rules(); err != nil {
+			return nil, err
+		}
+		foldCase = foldCase || platform != PlatformLinux
+		unicodeNormalized = unicodeNormalized || platform == PlatformDarwin
+	}
+
+	var issues []LintIssue
+	base := root.Piece()
+	siblings := map[APiece][]APiece{}
+	err := Walk(root, func(p APath, err error) error {
+		rel, _ := trimBase(base, p.Piece())
+		if err != nil || p.Type() == AInaccessible {
+			if err == nil {
+				err = p.Err()
+			}
+			issues = append(issues, LintIssue{Kind: LintUnreadable, Path: rel, Err: err})
+			return nil
+		}
+		if rel == "" {
+			return nil
+		}
+		dir := Dir(rel)
+		siblings[dir] = append(siblings[dir], rel)
+		for _, platform := range platforms {
+			for _, violation := range lintValidate(rel, platform, opts.WindowsRoot) {
+				issues = append(issues, LintIssue{Kind: LintViolation, Path: rel, Platform: platform, Violation: violation})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Collisions are per-directory, and only make sense once the directory is done,
+	// so they go after the walk; keep the output ordered by directory.
+	for _, dir := range sortedPieces(siblings) {
+		if unicodeNormalized {
+			nfc := Collation{Unicode: UnicodeNFC}
+			issues = append(issues, collisions(siblings[dir], LintUnicodeCollision, nfc, nil)...)
+		}
+		if foldCase && unicodeNormalized {
+			// "É" and "e\u0301" collide here too, but don't repeat the groups that
+			// are only a normalization collision.
+			issues = append(issues, collisions(siblings[dir], LintCaseCollision, CollationFoldNFD, distinctNFC)...)
+		} else if foldCase {
+			issues = append(issues, collisions(siblings[dir], LintCaseCollision, CollationFold, nil)...)
+		}
+	}
+	return issues, nil
+}
+
+// lintValidate checks a path relative to the tree root. For Windows, if there's
+// a root the tree is headed to, the whole-path length is checked with that
+// prepended.
+func lintValidate(rel APiece, platform Platform, windowsRoot APiece) []Violation {
+	violations := Validate(rel, platform)
+	if platform != PlatformWindows || windowsRoot == "" {
+		return violations
+	}
+	kept := violations[:0]
+	for _, violation := range violations {
+		if violation.Kind != ViolationPathTooLong {
+			kept = append(kept, violation)
+		}
+	}
+	rules := rulesFor[platform]
+	rebased := Join(windowsRoot, rel).String()
+	if length := rules.length(rebased); length > rules.maxPath {
+		kept = append(kept, Violation{
+			Kind: ViolationPathTooLong, Index: -1, Component: rebased,
+			Detail: fmt.Sprintf("%d > %d", length, rules.maxPath),
+		})
+	}
+	return kept
+}
+
+// collisions groups the paths by the collation key of their names, and returns an
+// issue for each group of more than one, optionally only if the group satisfies
+// report.
+func collisions(paths []APiece, kind LintKind, collation Collation, report func([]APiece) bool) []LintIssue {
+	groups := map[APiece][]APiece{}
+	var order []APiece
+	for _, p := range paths {
+		k := collation.Key(Base(p))
+		if groups[k] == nil {
+			order = append(order, k)
+		}
+		groups[k] = append(groups[k], p)
+	}
+	var issues []LintIssue
+	for _, k := range order {
+		if group := groups[k]; len(group) > 1 && (report == nil || report(group)) {
+			issues = append(issues, LintIssue{Kind: kind, Path: group[0], Collisions: group})
+		}
+	}
+	return issues
+}
+
+// distinctNFC is true if the group has names that don't just differ by Unicode
+// normalization, so that we don't report those twice.
+func distinctNFC(group []APiece) bool {
+	first := toNFC(Base(group[0]).String())
+	for _, p := range group[1:] {
+		if toNFC(Base(p).String()) != first {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedPieces[V any](m map[APiece]V) []APiece {
+	keys := make([]APiece, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
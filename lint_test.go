@@ -0,0 +1,142 @@
+package apathy
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// caseSensitiveTempDir returns a temporary directory, skipping the test if the
+// filesystem can't hold names that differ only by case or normalization.
+func caseSensitiveTempDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	makeTree(t, dir, "probe", "PROBE", "caf\u00e9", "cafe\u0301")
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	for _, entry := range entries {
+		require.NoError(t, os.Remove(filepath.Join(dir, entry.Name())))
+	}
+	if len(entries) != 4 {
+		t.Skip("filesystem folds case or normalizes names")
+	}
+	return dir
+}
+
+func TestLint(t *testing.T) {
+	t.Parallel()
+
+	dir := caseSensitiveTempDir(t)
+	makeTree(t, dir,
+		"assets/Texture.png", "assets/texture.png", "assets/TEXTURE.PNG", "assets/other.png",
+		"docs/caf\u00e9.md", "docs/cafe\u0301.md", "docs/CAF\u00c9.md",
+		"src/aux.c", "src/fine.c", "src/what?.c", "src/trailing.",
+	)
+	root := MustNewAPath(NewAPiece(dir))
+
+	issues, err := Lint(root, LintOptions{})
+	require.NoError(t, err)
+	require.Len(t, issues, 6)
+
+	// Violations come in walk order.
+	for idx, expected := range []string{
+		`src/aux.c: windows: reserved name: "aux.c"`,
+		`src/trailing.: windows: trailing dot or space: "trailing."`,
+		`src/what?.c: windows: forbidden character: "what?.c" ('?')`,
+	} {
+		assert.Equal(t, LintViolation, issues[idx].Kind)
+		assert.Equal(t, PlatformWindows, issues[idx].Platform)
+		assert.Equal(t, expected, issues[idx].String())
+	}
+
+	// Then collisions, by directory.
+	assert.Equal(t, LintIssue{
+		Kind:       LintCaseCollision,
+		Path:       "assets/TEXTURE.PNG",
+		Collisions: []APiece{"assets/TEXTURE.PNG", "assets/Texture.png", "assets/texture.png"},
+	}, issues[3])
+	assert.Equal(t, LintIssue{
+		Kind:       LintUnicodeCollision,
+		Path:       "docs/cafe\u0301.md",
+		Collisions: []APiece{"docs/cafe\u0301.md", "docs/caf\u00e9.md"},
+	}, issues[4])
+	// The names are escaped, otherwise they'd look the same.
+	assert.Equal(t, "docs/cafe\u0301.md: normalization collision between "+`"docs/cafe\u0301.md", "docs/caf\u00e9.md"`,
+		issues[4].String())
+	// On macOS "CAFÉ" collides with both, and that's reported as a case collision.
+	assert.Equal(t, LintIssue{
+		Kind:       LintCaseCollision,
+		Path:       "docs/CAF\u00c9.md",
+		Collisions: []APiece{"docs/CAF\u00c9.md", "docs/cafe\u0301.md", "docs/caf\u00e9.md"},
+	}, issues[5])
+
+	// Windows doesn't normalize, so there "CAFÉ" only collides with "café".
+	issues, err = Lint(root, LintOptions{Platforms: []Platform{PlatformWindows}})
+	require.NoError(t, err)
+	require.Len(t, issues, 5)
+	assert.Equal(t, []APiece{"docs/CAF\u00c9.md", "docs/caf\u00e9.md"}, issues[4].Collisions)
+
+	// Linux minds none of it.
+	issues, err = Lint(root, LintOptions{Platforms: []Platform{PlatformLinux}})
+	require.NoError(t, err)
+	assert.Empty(t, issues)
+}
+
+func TestLint_WindowsRoot(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	name := strings.Repeat("x", 100)
+	makeTree(t, dir, name+"/"+name+".txt")
+	root := MustNewAPath(NewAPiece(dir))
+	windows := []Platform{PlatformWindows}
+
+	// Relative to the root, it's short enough.
+	issues, err := Lint(root, LintOptions{Platforms: windows})
+	require.NoError(t, err)
+	assert.Empty(t, issues)
+
+	// But not once it's under a deep enough folder.
+	winRoot := APiece("C:/Users/builder/" + strings.Repeat("y", 50))
+	issues, err = Lint(root, LintOptions{Platforms: windows, WindowsRoot: winRoot})
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.Equal(t, APiece(name+"/"+name+".txt"), issues[0].Path)
+	assert.Equal(t, ViolationPathTooLong, issues[0].Violation.Kind)
+	assert.Equal(t, Join(winRoot, APiece(name), APiece(name+".txt")).String(), issues[0].Violation.Component)
+	assert.Equal(t, "273 > 259", issues[0].Violation.Detail)
+}
+
+func TestLint_Errors(t *testing.T) {
+	// Can't be parallel because it modifies globals.
+	dir := t.TempDir()
+	makeTree(t, dir, "file.txt", "locked/inside.txt")
+
+	_, err := Lint(MustNewAPath(NewAPiece(dir), "file.txt"), LintOptions{})
+	assert.ErrorIs(t, err, ErrNotDir)
+	_, err = Lint(MustNewAPath(NewAPiece(dir), "missing"), LintOptions{})
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	_, err = Lint(MustNewAPath(NewAPiece(dir)), LintOptions{Platforms: []Platform{PlatformLinux, Platform(99)}})
+	assert.ErrorIs(t, err, ErrUnknownPlatform)
+
+	denied := errors.New("denied")
+	realReadDir := ReadDir
+	defer withSaved(&ReadDir, func(name string) ([]os.DirEntry, error) {
+		if filepath.Base(name) == "locked" {
+			return nil, denied
+		}
+		return realReadDir(name)
+	})()
+	issues, err := Lint(MustNewAPath(NewAPiece(dir)), LintOptions{})
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.Equal(t, LintUnreadable, issues[0].Kind)
+	assert.Equal(t, APiece("locked"), issues[0].Path)
+	assert.ErrorIs(t, issues[0].Err, denied)
+}
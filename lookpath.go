@@ -0,0 +1,173 @@
+package apathy
+
+import (
+	"strings"
+)
+
+// defaultPathExt is what Windows uses when PATHEXT isn't set.
+const defaultPathExt = ".COM;.EXE;.BAT;.CMD"
+
+// SplitList splits a PATH-style list into clean APieces.
+//
+// With FlavourPosix, the list is ':'-separated, and an empty element means the
+// current directory, per POSIX. Because lists on mixed systems sometimes carry
+// Windows paths, a single letter followed by ":/" or ":\" at the start of an
+// element is taken as a drive letter rather than a separator, so that
+// "c:/tools:/usr/bin" gives "c:/tools" and "/usr/bin".
+//
+// With FlavourWindows, the list is ';'-separated, double-quotes protect
+// separators within an element and are removed, and empty elements are dropped.
+func SplitList(list string, flavour Flavour) []APiece {
+	if list == "" {
+		return nil
+	}
+	if flavour == FlavourWindows {
+		return splitWindowsList(list)
+	}
+	return splitPosixList(list)
+}
+
+func splitPosixList(list string) []APiece {
+	var pieces []APiece
+	start := 0
+	for idx := 0; idx <= len(list); idx++ {
+		if idx < len(list) && list[idx] != ':' {
+			continue
+		}
+		// Don't split "c:/..." after the drive letter.
+		if idx == start+1 && idx+1 < len(list) && (list[idx+1] == '/' || list[idx+1] == '\\') &&
+			hasDriveLetter(APiece(list[start:idx+1])) {
+			continue
+		}
+		pieces = append(pieces, NewAPiece(list[start:idx]))
+		start = idx + 1
+	}
+	return pieces
+}
+
+func splitWindowsList(list string) []APiece {
+	var pieces []APiece
+	var element strings.Builder
+	quoted := false
+	flush := func() {
+		if element.Len() > 0 {
+			pieces = append(pieces, NewAPiece(element.String()))
+			element.Reset()
+		}
+	}
+	for idx := 0; idx < len(list); idx++ {
+		switch c := list[idx]; {
+		case c == '"':
+			quoted = !quoted
+		case c == ';' && !quoted:
+			flush()
+		default:
+			element.WriteByte(c)
+		}
+	}
+	flush()
+	return pieces
+}
+
+// LookPath searches the directories in the PATH environment variable for an
+// executable called name, and returns an APath for it. See LookPathFor.
+func LookPath(name string) (APath, error) {
+	return LookPathFor(NativeFlavour, name)
+}
+
+// LookPathFor searches for an executable using the given Flavour's rules. If name
+// contains a separator it is checked directly, without consulting PATH.
+//
+// With FlavourPosix, an executable is a regular file (or a link to one) with an
+// execute bit set. With FlavourWindows, any regular file will do, but the name is
+// tried with each of the extensions in PATHEXT appended, unless it already ends in
+// one of them, in which case it is tried as-is first.
+//
+// Like os/exec, relative directories in PATH are ignored. The returned APath
+// reflects the Lstat of what was found, so it may be a symlink.
+func LookPathFor(flavour Flavour, name string) (APath, error) {
+	notFound := &PathError{Op: "LookPath", Piece: NewAPiece(name), Err: ErrNotFound}
+	if name == "" {
+		return nil, notFound
+	}
+	candidates := lookPathCandidates(flavour, name)
+
+	separators := "/"
+	if flavour == FlavourWindows {
+		separators = `/\`
+	}
+	var dirs []APiece
+	if strings.ContainsAny(name, separators) {
+		dirs = []APiece{""}
+	} else {
+		pathList, _ := LookupEnv("PATH")
+		dirs = SplitList(pathList, flavour)
+	}
+	for _, dir := range dirs {
+		if dir != "" && !dir.IsAbs() {
+			continue
+		}
+		if p, err := lookPathIn(flavour, dir, candidates); p != nil || err != nil {
+			return p, err
+		}
+	}
+	return nil, notFound
+}
+
+// lookPathCandidates lists the names to try for an executable.
+func lookPathCandidates(flavour Flavour, name string) []string {
+	if flavour != FlavourWindows {
+		return []string{name}
+	}
+	pathExt, ok := LookupEnv("PATHEXT")
+	if !ok || pathExt == "" {
+		pathExt = defaultPathExt
+	}
+	var exts []string
+	for _, ext := range strings.Split(pathExt, ";") {
+		if ext != "" && ext[0] == '.' {
+			exts = append(exts, ext)
+		}
+	}
+
+	var candidates []string
+	nameExt := Ext(NewAPiece(name)).String()
+	for _, ext := range exts {
+		if strings.EqualFold(ext, nameExt) {
+			candidates = append(candidates, name)
+			break
+		}
+	}
+	for _, ext := range exts {
+		candidates = append(candidates, name+ext)
+	}
+	return candidates
+}
+
+// lookPathIn returns the APath of the first candidate in dir that is executable,
+// or nil if there isn't one. An empty dir means the candidates are paths already.
+func lookPathIn(flavour Flavour, dir APiece, candidates []string) (APath, error) {
+	for _, candidate := range candidates {
+		pieces := []APiece{NewAPiece(candidate)}
+		if dir != "" {
+			pieces = []APiece{dir, pieces[0]}
+		}
+		piece, err := resolvePieces(pieces...)
+		if err != nil {
+			return nil, err
+		}
+		if isExecutable(flavour, piece) {
+			return NewAPath(piece)
+		}
+	}
+	return nil, nil
+}
+
+func isExecutable(flavour Flavour, piece APiece) bool {
+	// Use Stat rather than Lstat: PATH is full of symlinks to the real binaries.
+	info, err := Stat(piece.String())
+	if err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+	return flavour == FlavourWindows || info.Mode().Perm()&0111 != 0
+}
@@ -0,0 +1,120 @@
+package apathy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitList(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		list     string
+		flavour  Flavour
+		expected []APiece
+	}{
+		{"empty posix", "", FlavourPosix, nil},
+		{"empty windows", "", FlavourWindows, nil},
+		{"posix", "/usr/local/bin:/usr/bin/:/bin", FlavourPosix, []APiece{"/usr/local/bin", "/usr/bin", "/bin"}},
+		{"posix empty means dot", "/bin::/sbin:", FlavourPosix, []APiece{"/bin", ".", "/sbin", "."}},
+		{"posix drives", `c:/tools:/usr/bin:D:\bin:e:`, FlavourPosix, []APiece{"c:/tools", "/usr/bin", "D:/bin", "e", "."}},
+		{"posix not a drive", "ab:/x:c", FlavourPosix, []APiece{"ab", "/x", "c"}},
+		{"windows", `C:\Windows\system32;C:\Windows;;c:/tools\`, FlavourWindows, []APiece{"C:/Windows/system32", "C:/Windows", "c:/tools"}},
+		{"windows quoted", `"C:\semi;colon";C:\bin`, FlavourWindows, []APiece{"C:/semi;colon", "C:/bin"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, SplitList(tc.list, tc.flavour))
+		})
+	}
+}
+
+func TestFlavour(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "posix", FlavourPosix.String())
+	assert.Equal(t, "windows", FlavourWindows.String())
+	assert.Equal(t, byte(':'), FlavourPosix.ListSeparator())
+	assert.Equal(t, byte(';'), FlavourWindows.ListSeparator())
+	assert.Equal(t, onWindows, NativeFlavour == FlavourWindows)
+}
+
+func TestLookPathFor(t *testing.T) {
+	// Can't be parallel because it modifies globals.
+	if onWindows {
+		t.Skip("relies on posix permission bits")
+	}
+
+	dir := t.TempDir()
+	bin1, bin2 := filepath.Join(dir, "bin1"), filepath.Join(dir, "bin2")
+	require.NoError(t, os.Mkdir(bin1, 0755))
+	require.NoError(t, os.Mkdir(bin2, 0755))
+	writeFile := func(name string, perm os.FileMode) {
+		require.NoError(t, os.WriteFile(name, nil, perm))
+		require.NoError(t, os.Chmod(name, perm))
+	}
+	writeFile(filepath.Join(bin1, "tool"), 0644) // not executable
+	writeFile(filepath.Join(bin2, "tool"), 0755)
+	writeFile(filepath.Join(bin2, "conv.EXE"), 0644)
+	writeFile(filepath.Join(bin2, "script.CMD"), 0644)
+	require.NoError(t, os.Symlink(filepath.Join(bin2, "tool"), filepath.Join(bin1, "linked")))
+	require.NoError(t, os.Mkdir(filepath.Join(bin1, "dir"), 0755))
+
+	env := map[string]string{"PATH": "relative:" + bin1 + ":" + bin2}
+	defer withSaved(&LookupEnv, func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	})()
+
+	for _, tc := range []struct {
+		name     string
+		flavour  Flavour
+		input    string
+		expected string
+	}{
+		{"skips non-executable", FlavourPosix, "tool", filepath.Join(bin2, "tool")},
+		{"symlink", FlavourPosix, "linked", filepath.Join(bin1, "linked")},
+		{"directory", FlavourPosix, "dir", ""},
+		{"missing", FlavourPosix, "nope", ""},
+		{"empty", FlavourPosix, "", ""},
+		{"explicit path", FlavourPosix, filepath.Join(bin2, "tool"), filepath.Join(bin2, "tool")},
+		{"explicit non-executable", FlavourPosix, filepath.Join(bin1, "tool"), ""},
+		{"windows no ext", FlavourWindows, "conv", filepath.Join(bin2, "conv.EXE")},
+		{"windows ext given", FlavourWindows, "conv.exe", ""},
+		{"windows cmd", FlavourWindows, "script", filepath.Join(bin2, "script.CMD")},
+		{"windows unlisted ext", FlavourWindows, "tool", ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.flavour == FlavourWindows {
+				env["PATH"] = bin1 + ";" + bin2
+				defer func() { env["PATH"] = "relative:" + bin1 + ":" + bin2 }()
+			}
+			p, err := LookPathFor(tc.flavour, tc.input)
+			if tc.expected == "" {
+				assert.ErrorIs(t, err, ErrNotFound)
+				assert.Nil(t, p)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, NewAPiece(tc.expected), p.Piece())
+		})
+	}
+
+	// PATHEXT controls which extensions are tried, and an extension in the list is tried as-is.
+	env["PATH"] = bin2
+	env["PATHEXT"] = ".EXE;.Cmd"
+	p, err := LookPathFor(FlavourWindows, "conv.EXE")
+	require.NoError(t, err)
+	assert.Equal(t, NewAPiece(filepath.Join(bin2, "conv.EXE")), p.Piece())
+	_, err = LookPathFor(FlavourWindows, "script.bat")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// LookPath is the native flavour.
+	p, err = LookPath("tool")
+	require.NoError(t, err)
+	assert.True(t, p.IsFile())
+}
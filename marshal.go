@@ -0,0 +1,121 @@
+package apathy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// MarshalText implements encoding.TextMarshaler, producing the posix form of the piece.
+// Text, and so JSON, has to be UTF-8, and encoding/json would quietly replace other
+// bytes with U+FFFD, so a piece that isn't valid UTF-8 is an error wrapping ErrNotUTF8.
+// Use the binary codec, or EscapeDisplay, for those.
+func (p APiece) MarshalText() ([]byte, error) {
+	if !p.IsValidUTF8() {
+		return nil, &PathError{Op: "MarshalText", Piece: p, Err: ErrNotUTF8}
+	}
+	return []byte(p), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. The text goes through NewAPiece,
+// so an APiece read from a config file or manifest is as clean as any other.
+func (p *APiece) UnmarshalText(text []byte) error {
+	*p = NewAPiece(string(text))
+	return nil
+}
+
+// ParseAPathType is the inverse of APathType.String().
+func ParseAPathType(name string) (APathType, error) {
+	for t, typeName := range aPathTypeNames {
+		if typeName == name {
+			return APathType(t), nil
+		}
+	}
+	return ATypeUnknown, fmt.Errorf("%w: %q", ErrUnknownAPathType, name)
+}
+
+// MarshalText implements encoding.TextMarshaler using the name of the type.
+func (a APathType) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, accepting the names produced by String().
+func (a *APathType) UnmarshalText(text []byte) error {
+	t, err := ParseAPathType(string(text))
+	if err != nil {
+		return err
+	}
+	*a = t
+	return nil
+}
+
+// aPathJSON is the serialized form of an APath.
+type aPathJSON struct {
+	Path    APiece     `json:"path"`
+	Type    APathType  `json:"type"`
+	ModTime *time.Time `json:"mtime,omitempty"`
+	Size    int64      `json:"size,omitempty"`
+	Err     string     `json:"error,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler, recording the path along with the type,
+// mtime and size captured by the last Lstat.
+func (p *aPath) MarshalJSON() ([]byte, error) {
+	record := aPathJSON{Path: p.APiece, Type: p.aType, Size: p.size}
+	if !p.mtime.IsZero() {
+		record.ModTime = &p.mtime
+	}
+	if p.err != nil {
+		record.Err = p.err.Error()
+	}
+	return json.Marshal(record)
+}
+
+// UnmarshalAPathJSON rebuilds an APath from the output of json.Marshal, trusting
+// the recorded metadata rather than re-stating the path.
+func UnmarshalAPathJSON(data []byte) (APath, error) {
+	var record aPathJSON
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, err
+	}
+	if !record.Path.IsAbs() {
+		return nil, &PathError{Op: "UnmarshalAPathJSON", Piece: record.Path, Err: ErrNotAbsolute}
+	}
+	p := &aPath{APiece: record.Path, aType: record.Type, size: record.Size}
+	if record.ModTime != nil {
+		p.mtime = *record.ModTime
+	}
+	if record.Err != "" {
+		p.err = errors.New(record.Err)
+	}
+	return p, nil
+}
+
+// APathJSON wraps an APath so that it can be a field of a struct that is
+// round-tripped through encoding/json, which can't decode into an interface.
+type APathJSON struct {
+	APath
+}
+
+// MarshalJSON implements json.Marshaler; a nil APath is encoded as null.
+func (j APathJSON) MarshalJSON() ([]byte, error) {
+	if j.APath == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(j.APath)
+}
+
+// UnmarshalJSON implements json.Unmarshaler via UnmarshalAPathJSON.
+func (j *APathJSON) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		j.APath = nil
+		return nil
+	}
+	p, err := UnmarshalAPathJSON(data)
+	if err != nil {
+		return err
+	}
+	j.APath = p
+	return nil
+}
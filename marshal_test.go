@@ -0,0 +1,128 @@
+package apathy
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPiece_MarshalText(t *testing.T) {
+	t.Parallel()
+
+	text, err := APiece("c:/windows").MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, "c:/windows", string(text))
+
+	var piece APiece
+	assert.NoError(t, piece.UnmarshalText([]byte(`c:\windows\system32\..\notepad.exe`)))
+	assert.Equal(t, APiece("c:/windows/notepad.exe"), piece)
+
+	// And via encoding/json, which uses the TextMarshaler for strings and map keys.
+	var config struct {
+		Src  APiece            `json:"src"`
+		Dirs map[APiece]APiece `json:"dirs"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"src": "a\\b/../c", "dirs": {"x\\y": "./z"}}`), &config))
+	assert.Equal(t, APiece("a/c"), config.Src)
+	assert.Equal(t, map[APiece]APiece{"x/y": "z"}, config.Dirs)
+
+	// JSON can't hold bytes that aren't UTF-8, so refuse rather than mangle them.
+	_, err = APiece("caf\xe9/\xff").MarshalText()
+	assert.ErrorIs(t, err, ErrNotUTF8)
+	_, err = json.Marshal(APiece("a/\xff"))
+	assert.ErrorIs(t, err, ErrNotUTF8)
+	_, err = json.Marshal(map[APiece]int{"a/\xff": 1})
+	assert.ErrorIs(t, err, ErrNotUTF8)
+	_, err = json.Marshal(&aPath{APiece: "/a/\xff", aType: ATypeFile})
+	assert.ErrorIs(t, err, ErrNotUTF8)
+}
+
+func TestAPathType_MarshalText(t *testing.T) {
+	t.Parallel()
+
+	for kind := ANotExist; kind < numAPathTypes; kind++ {
+		text, err := kind.MarshalText()
+		require.NoError(t, err)
+		var parsed APathType
+		require.NoError(t, parsed.UnmarshalText(text))
+		assert.Equal(t, kind, parsed)
+	}
+
+	var parsed APathType
+	assert.ErrorIs(t, parsed.UnmarshalText([]byte("Teapot")), ErrUnknownAPathType)
+}
+
+func TestAPath_JSON(t *testing.T) {
+	t.Parallel()
+
+	mtime := time.Date(2025, 2, 5, 12, 34, 56, 789, time.UTC)
+	for _, tc := range []struct {
+		name  string
+		apath *aPath
+		json  string
+	}{
+		{"file", &aPath{APiece: "/a/file", aType: ATypeFile, mtime: mtime, size: 42},
+			`{"path":"/a/file","type":"File","mtime":"2025-02-05T12:34:56.000000789Z","size":42}`},
+		{"missing", &aPath{APiece: "c:/missing"},
+			`{"path":"c:/missing","type":"NotExist"}`},
+		{"inaccessible", &aPath{APiece: "/secret", aType: AInaccessible, err: errors.New("denied")},
+			`{"path":"/secret","type":"Inaccessible","error":"denied"}`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := json.Marshal(APath(tc.apath))
+			require.NoError(t, err)
+			assert.JSONEq(t, tc.json, string(data))
+
+			decoded, err := UnmarshalAPathJSON(data)
+			require.NoError(t, err)
+			assert.Equal(t, tc.apath.Piece(), decoded.Piece())
+			assert.Equal(t, tc.apath.Type(), decoded.Type())
+			assert.True(t, tc.apath.ModTime().Equal(decoded.ModTime()))
+			assert.Equal(t, tc.apath.Size(), decoded.Size())
+			if tc.apath.err != nil {
+				assert.EqualError(t, decoded.Err(), tc.apath.err.Error())
+			} else {
+				assert.NoError(t, decoded.Err())
+			}
+		})
+	}
+}
+
+func TestUnmarshalAPathJSON_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := UnmarshalAPathJSON([]byte(`{"path": "relative", "type": "File"}`))
+	assert.ErrorIs(t, err, ErrNotAbsolute)
+	_, err = UnmarshalAPathJSON([]byte(`{"path": "/x", "type": "Teapot"}`))
+	assert.ErrorIs(t, err, ErrUnknownAPathType)
+	_, err = UnmarshalAPathJSON([]byte(`[]`))
+	assert.Error(t, err)
+}
+
+func TestAPathJSON(t *testing.T) {
+	t.Parallel()
+
+	type manifest struct {
+		Entries []APathJSON `json:"entries"`
+	}
+	in := manifest{Entries: []APathJSON{
+		{&aPath{APiece: "/x/y", aType: ATypeDir}},
+		{nil},
+	}}
+	data, err := json.Marshal(in)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"entries":[{"path":"/x/y","type":"Dir"},null]}`, string(data))
+
+	var out manifest
+	require.NoError(t, json.Unmarshal(data, &out))
+	require.Len(t, out.Entries, 2)
+	assert.Equal(t, APiece("/x/y"), out.Entries[0].Piece())
+	assert.True(t, out.Entries[0].IsDir())
+	assert.Nil(t, out.Entries[1].APath)
+
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"entries":[{"path":"x"}]}`), &out), ErrNotAbsolute)
+}
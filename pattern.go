@@ -0,0 +1,401 @@
+package apathy
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"unicode/utf8"
+)
+
+// Pattern is a compiled glob pattern for matching against clean, posix-style
+// APieces, so that the same include rules work on every platform. Compile once
+// with NewPattern and reuse it; a Pattern is safe for concurrent use.
+//
+// The syntax is that of path.Match, plus brace alternation and recursion:
+//
+//	syntax     matches
+//	*          any sequence of characters within a component, including none
+//	?          any one character within a component
+//	[abc]      one of the characters listed, or in a range such as [a-z];
+//	[!abc]     or [^abc], one character not listed
+//	{a,b}      either alternative, which may contain '/' and nest
+//	**         as a whole component, any number of components, including none
+//	\c         the character c, literally
+//
+// Matching is case-sensitive and byte-exact; see Collation.Key for preparing
+// pieces and patterns that should match regardless of case.
+type Pattern struct {
+	source       string
+	alternatives [][]globSegment
+}
+
+// globSegment matches one component of a path, or with recursive set, any number.
+type globSegment struct {
+	recursive bool
+	literal   string // if tokens is nil, the component must equal this
+	tokens    []globToken
+}
+
+type globTokenKind uint8
+
+const (
+	globLiteral globTokenKind = iota
+	globAny
+	globStar
+	globClass
+)
+
+type globToken struct {
+	kind    globTokenKind
+	literal string
+	ranges  []globRange
+	negate  bool
+}
+
+type globRange struct {
+	lo, hi rune
+}
+
+// NewPattern compiles a glob pattern; see Pattern for the syntax. A malformed
+// pattern is an ErrBadSyntax.
+func NewPattern(pattern string) (*Pattern, error) {
+	expanded, err := expandBraces(pattern)
+	if err != nil {
+		return nil, &PathError{Op: "NewPattern", Piece: APiece(pattern), Err: err}
+	}
+	compiled := &Pattern{source: pattern}
+	for _, alternative := range expanded {
+		var segments []globSegment
+		for _, component := range strings.Split(path.Clean(alternative), "/") {
+			segment, err := compileSegment(component)
+			if err != nil {
+				return nil, &PathError{Op: "NewPattern", Piece: APiece(pattern), Err: err}
+			}
+			segments = append(segments, segment)
+		}
+		compiled.alternatives = append(compiled.alternatives, segments)
+	}
+	return compiled, nil
+}
+
+// MustNewPattern is NewPattern for patterns known to be good, e.g. constants;
+// it panics on error.
+func MustNewPattern(pattern string) *Pattern {
+	return must(NewPattern(pattern))
+}
+
+// Match compiles the pattern and matches it against the piece. Use NewPattern for
+// patterns that will be used more than once.
+func Match(pattern string, piece Piecer) (bool, error) {
+	compiled, err := NewPattern(pattern)
+	if err != nil {
+		return false, err
+	}
+	return compiled.Match(piece), nil
+}
+
+// String returns the pattern as given to NewPattern.
+func (p *Pattern) String() string {
+	return p.source
+}
+
+// Match returns true if the whole piece matches the pattern.
+func (p *Pattern) Match(piece Piecer) bool {
+	names := strings.Split(piece.Piece().String(), "/")
+	for _, segments := range p.alternatives {
+		if states := runSegments(segments, names); states != nil && states[len(segments)] {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesUnder returns true if something beneath dir could match the pattern,
+// meaning that a walk looking for matches needs to descend into it. A dir of ""
+// or "." is the top of the tree.
+func (p *Pattern) matchesUnder(dir APiece) bool {
+	var names []string
+	if dir != "" && dir != Dot {
+		names = strings.Split(dir.String(), "/")
+	}
+	for _, segments := range p.alternatives {
+		states := runSegments(segments, names)
+		for _, reached := range states[:max(len(states)-1, 0)] {
+			if reached {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// runSegments matches the names against the segments, tracking every position in
+// the pattern that could have been reached, since "**" can consume any number of
+// names. Position len(segments) means the whole pattern was consumed. It returns
+// nil as soon as nothing is reachable.
+func runSegments(segments []globSegment, names []string) []bool {
+	current := make([]bool, len(segments)+1)
+	next := make([]bool, len(segments)+1)
+	current[0] = true
+	closeRecursive(segments, current)
+	for _, name := range names {
+		clear(next)
+		reachable := false
+		for idx, segment := range segments {
+			switch {
+			case !current[idx]:
+			case segment.recursive:
+				next[idx], reachable = true, true
+			case segment.match(name):
+				next[idx+1], reachable = true, true
+			}
+		}
+		if !reachable {
+			return nil
+		}
+		closeRecursive(segments, next)
+		current, next = next, current
+	}
+	return current
+}
+
+// closeRecursive lets each "**" that was reached also match nothing.
+func closeRecursive(segments []globSegment, states []bool) {
+	for idx, segment := range segments {
+		if states[idx] && segment.recursive {
+			states[idx+1] = true
+		}
+	}
+}
+
+func (s globSegment) match(name string) bool {
+	if s.tokens == nil {
+		return name == s.literal
+	}
+	// The usual glob algorithm: on a mismatch, let the last '*' take one more
+	// character and try again from there.
+	tokenIdx, nameIdx := 0, 0
+	starToken, starName := -1, 0
+	for tokenIdx < len(s.tokens) || nameIdx < len(name) {
+		if tokenIdx < len(s.tokens) {
+			if s.tokens[tokenIdx].kind == globStar {
+				starToken, starName = tokenIdx, nameIdx
+				tokenIdx++
+				continue
+			}
+			if size := s.tokens[tokenIdx].match(name[nameIdx:]); size > 0 {
+				tokenIdx++
+				nameIdx += size
+				continue
+			}
+		}
+		if starToken < 0 || starName >= len(name) {
+			return false
+		}
+		_, size := utf8.DecodeRuneInString(name[starName:])
+		starName += size
+		tokenIdx, nameIdx = starToken+1, starName
+	}
+	return true
+}
+
+// match returns how many bytes at the start of name the token matches, or 0.
+func (t globToken) match(name string) int {
+	if name == "" {
+		return 0
+	}
+	if t.kind == globLiteral {
+		if strings.HasPrefix(name, t.literal) {
+			return len(t.literal)
+		}
+		return 0
+	}
+	r, size := utf8.DecodeRuneInString(name)
+	if t.kind == globAny {
+		return size
+	}
+	in := false
+	for _, rng := range t.ranges {
+		if rng.lo <= r && r <= rng.hi {
+			in = true
+			break
+		}
+	}
+	if in == t.negate {
+		return 0
+	}
+	return size
+}
+
+func compileSegment(component string) (globSegment, error) {
+	if component == "**" {
+		return globSegment{recursive: true}, nil
+	}
+	var tokens []globToken
+	literal := true
+	for idx := 0; idx < len(component); {
+		switch c := component[idx]; c {
+		case '*':
+			literal = false
+			if len(tokens) == 0 || tokens[len(tokens)-1].kind != globStar {
+				tokens = append(tokens, globToken{kind: globStar})
+			}
+			idx++
+		case '?':
+			literal = false
+			tokens = append(tokens, globToken{kind: globAny})
+			idx++
+		case '[':
+			literal = false
+			token, end, err := compileClass(component, idx)
+			if err != nil {
+				return globSegment{}, err
+			}
+			tokens = append(tokens, token)
+			idx = end
+		case '\\':
+			if idx+1 >= len(component) {
+				return globSegment{}, fmt.Errorf("%w: trailing backslash", ErrBadSyntax)
+			}
+			_, size := utf8.DecodeRuneInString(component[idx+1:])
+			tokens = append(tokens, globToken{kind: globLiteral, literal: component[idx+1 : idx+1+size]})
+			idx += 1 + size
+		default:
+			_, size := utf8.DecodeRuneInString(component[idx:])
+			tokens = append(tokens, globToken{kind: globLiteral, literal: component[idx : idx+size]})
+			idx += size
+		}
+	}
+	if literal {
+		var buf strings.Builder
+		for _, token := range tokens {
+			buf.WriteString(token.literal)
+		}
+		return globSegment{literal: buf.String()}, nil
+	}
+	return globSegment{tokens: tokens}, nil
+}
+
+// compileClass parses the character class starting at component[start], which is
+// '[', and returns it with the index just past its ']'.
+func compileClass(component string, start int) (globToken, int, error) {
+	token := globToken{kind: globClass}
+	idx := start + 1
+	if idx < len(component) && (component[idx] == '!' || component[idx] == '^') {
+		token.negate = true
+		idx++
+	}
+	readChar := func() (rune, error) {
+		if idx < len(component) && component[idx] == '\\' {
+			idx++
+		}
+		if idx >= len(component) {
+			return 0, fmt.Errorf("%w: unterminated character class", ErrBadSyntax)
+		}
+		r, size := utf8.DecodeRuneInString(component[idx:])
+		idx += size
+		return r, nil
+	}
+	for first := true; ; first = false {
+		if idx >= len(component) {
+			return token, 0, fmt.Errorf("%w: unterminated character class", ErrBadSyntax)
+		}
+		// A ']' straight after the '[' or '[!' is just a ']'.
+		if component[idx] == ']' && !first {
+			return token, idx + 1, nil
+		}
+		lo, err := readChar()
+		if err != nil {
+			return token, 0, err
+		}
+		hi := lo
+		if idx+1 < len(component) && component[idx] == '-' && component[idx+1] != ']' {
+			idx++
+			if hi, err = readChar(); err != nil {
+				return token, 0, err
+			}
+			if hi < lo {
+				return token, 0, fmt.Errorf("%w: bad range %q-%q", ErrBadSyntax, lo, hi)
+			}
+		}
+		token.ranges = append(token.ranges, globRange{lo, hi})
+	}
+}
+
+// expandBraces rewrites a pattern with {a,b} alternations into the list of
+// patterns without them, e.g. "x/{a,b{c,d}}" gives "x/a", "x/bc", "x/bd". Braces
+// inside character classes, or escaped, are left alone.
+func expandBraces(pattern string) ([]string, error) {
+	start, end := -1, -1
+	var commas []int
+	depth := 0
+scan:
+	for idx := 0; idx < len(pattern); idx++ {
+		switch pattern[idx] {
+		case '\\':
+			idx++
+		case '[':
+			if classClose := classEnd(pattern, idx); classClose > 0 {
+				idx = classClose
+			}
+		case '{':
+			if depth == 0 {
+				start = idx
+			}
+			depth++
+		case ',':
+			if depth == 1 {
+				commas = append(commas, idx)
+			}
+		case '}':
+			if depth == 0 {
+				return nil, fmt.Errorf("%w: unmatched '}'", ErrBadSyntax)
+			}
+			if depth--; depth == 0 {
+				end = idx
+				break scan
+			}
+		}
+	}
+	if depth > 0 {
+		return nil, fmt.Errorf("%w: unmatched '{'", ErrBadSyntax)
+	}
+	if start < 0 {
+		return []string{pattern}, nil
+	}
+
+	prefix, suffix := pattern[:start], pattern[end+1:]
+	bounds := append(append([]int{start}, commas...), end)
+	var expanded []string
+	for idx := 1; idx < len(bounds); idx++ {
+		alternative := pattern[bounds[idx-1]+1 : bounds[idx]]
+		// The alternative may have nested braces, and the suffix more of them.
+		more, err := expandBraces(prefix + alternative + suffix)
+		if err != nil {
+			return nil, err
+		}
+		expanded = append(expanded, more...)
+	}
+	return expanded, nil
+}
+
+// classEnd returns the index of the ']' closing the character class that starts
+// at pattern[start], or -1 if it isn't closed.
+func classEnd(pattern string, start int) int {
+	idx := start + 1
+	if idx < len(pattern) && (pattern[idx] == '!' || pattern[idx] == '^') {
+		idx++
+	}
+	if idx < len(pattern) && pattern[idx] == ']' {
+		idx++
+	}
+	for ; idx < len(pattern); idx++ {
+		switch pattern[idx] {
+		case '\\':
+			idx++
+		case ']':
+			return idx
+		}
+	}
+	return -1
+}
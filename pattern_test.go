@@ -0,0 +1,125 @@
+package apathy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		pattern, piece string
+		expected       bool
+	}{
+		{"a/b.png", "a/b.png", true},
+		{"a/b.png", "a/b.PNG", false},
+		{"*.png", "b.png", true},
+		{"*.png", "a/b.png", false},
+		{"a/*", "a/b", true},
+		{"a/*", "a/b/c", false},
+		{"a*b*c", "abxbyc", true},
+		{"a*b*c", "abxbyd", false},
+		{"?.txt", "a.txt", true},
+		{"?.txt", "ab.txt", false},
+		{"?.txt", "é.txt", true},
+		{"?.txt", "\xff.txt", true},
+		{"[abc].go", "b.go", true},
+		{"[a-c].go", "d.go", false},
+		{"[!a-c].go", "d.go", true},
+		{"[^a-c].go", "a.go", false},
+		{"[]x].go", "].go", true},
+		{"[a-].go", "-.go", true},
+		{`[\]].go`, "].go", true},
+		{`\*.go`, "*.go", true},
+		{`\*.go`, "x.go", false},
+		{"{a,b}.go", "b.go", true},
+		{"{a,b}.go", "c.go", false},
+		{"x/{a,b/c}/y", "x/b/c/y", true},
+		{"{a,b{c,d}}", "bd", true},
+		{"{,x}y", "y", true},
+		{"[{].go", "{.go", true},
+		{"**", "a/b/c", true},
+		{"**/*.png", "a.png", true},
+		{"**/*.png", "a/b/c.png", true},
+		{"**/*.png", "a/b/c.jpg", false},
+		{"a/**/b", "a/b", true},
+		{"a/**/b", "a/x/y/b", true},
+		{"a/**/b", "a/x/y/c", false},
+		{"a/**", "a/x/y", true},
+		{"a/**/*/c", "a/b/c", true},
+		{"a**b", "axxb", true},
+		{"a**b", "ax/xb", false},
+		{"/src/**/*.go", "/src/x/y.go", true},
+		{"/src/**/*.go", "src/x/y.go", false},
+		{"./a/../b/*.go", "b/x.go", true},
+	} {
+		t.Run(tc.pattern+" "+tc.piece, func(t *testing.T) {
+			matched, err := Match(tc.pattern, APiece(tc.piece))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, matched)
+		})
+	}
+}
+
+func TestNewPattern_Errors(t *testing.T) {
+	t.Parallel()
+
+	for _, pattern := range []string{"[abc", "[]", "[z-a]", `a\`, "{a,b", "a}", "{[a,b}"} {
+		_, err := NewPattern(pattern)
+		assert.ErrorIs(t, err, ErrBadSyntax, pattern)
+		var pathErr *PathError
+		if assert.ErrorAs(t, err, &pathErr, pattern) {
+			assert.Equal(t, APiece(pattern), pathErr.Piece)
+		}
+	}
+	_, err := Match("[", APiece("x"))
+	assert.ErrorIs(t, err, ErrBadSyntax)
+	assert.Panics(t, func() { MustNewPattern("{") })
+}
+
+func TestPattern_Reuse(t *testing.T) {
+	t.Parallel()
+
+	pattern := MustNewPattern("assets/{textures,models}/**/*.{png,obj}")
+	assert.Equal(t, "assets/{textures,models}/**/*.{png,obj}", pattern.String())
+	matched := 0
+	for _, piece := range []APiece{
+		"assets/textures/a.png", "assets/models/x/y/z.obj", "assets/models/z.png",
+		"assets/sounds/a.png", "assets/textures/a.wav", "other/textures/a.png",
+	} {
+		if pattern.Match(piece) {
+			matched++
+		}
+	}
+	assert.Equal(t, 3, matched)
+}
+
+func TestPattern_matchesUnder(t *testing.T) {
+	t.Parallel()
+
+	pattern := MustNewPattern("assets/{textures,models/*}/**/*.png")
+	for _, tc := range []struct {
+		dir      APiece
+		expected bool
+	}{
+		{".", true},
+		{"", true},
+		{"assets", true},
+		{"src", false},
+		{"assets/textures", true},
+		{"assets/textures/a/b/c", true},
+		{"assets/models", true},
+		{"assets/models/car", true},
+		{"assets/sounds", false},
+	} {
+		assert.Equal(t, tc.expected, pattern.matchesUnder(tc.dir), tc.dir)
+	}
+
+	// Nothing can be under a complete match of a pattern without "**".
+	pattern = MustNewPattern("a/b")
+	assert.True(t, pattern.matchesUnder("a"))
+	assert.False(t, pattern.matchesUnder("a/b"))
+}
@@ -0,0 +1,80 @@
+package apathy
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// Platform identifies a target operating system for portability checks, where the
+// rules differ by more than just the Flavour.
+type Platform uint8
+
+const (
+	PlatformLinux   Platform = iota // PlatformLinux is Linux and other unix-likes.
+	PlatformDarwin                  // PlatformDarwin is macOS.
+	PlatformWindows                 // PlatformWindows is Windows.
+)
+
+// NativePlatform is the Platform we're running on.
+var NativePlatform = platformForGOOS(runtime.GOOS)
+
+func platformForGOOS(goos string) Platform {
+	switch goos {
+	case "windows":
+		return PlatformWindows
+	case "darwin", "ios":
+		return PlatformDarwin
+	default:
+		return PlatformLinux
+	}
+}
+
+func (p Platform) String() string {
+	switch p {
+	case PlatformLinux:
+		return "linux"
+	case PlatformDarwin:
+		return "darwin"
+	case PlatformWindows:
+		return "windows"
+	default:
+		return fmt.Sprintf("Platform(%d)", uint8(p))
+	}
+}
+
+// ParsePlatform accepts the names produced by String, plus "macos".
+func ParsePlatform(name string) (Platform, error) {
+	switch strings.ToLower(name) {
+	case "linux":
+		return PlatformLinux, nil
+	case "darwin", "macos":
+		return PlatformDarwin, nil
+	case "windows":
+		return PlatformWindows, nil
+	default:
+		return PlatformLinux, fmt.Errorf("%w: %q", ErrUnknownPlatform, name)
+	}
+}
+
+// Flavour returns the Flavour of lexical conventions the platform follows.
+func (p Platform) Flavour() Flavour {
+	if p == PlatformWindows {
+		return FlavourWindows
+	}
+	return FlavourPosix
+}
+
+// Collation returns how the platform's usual filesystem compares names: NTFS folds
+// case, APFS folds case and Unicode normalization, and Linux filesystems do
+// neither. Individual volumes can be configured otherwise.
+func (p Platform) Collation() Collation {
+	switch p {
+	case PlatformWindows:
+		return CollationFold
+	case PlatformDarwin:
+		return CollationFoldNFD
+	default:
+		return CollationExact
+	}
+}
@@ -0,0 +1,174 @@
+package apathy
+
+import "strings"
+
+// Shell identifies a command interpreter whose quoting rules we know.
+type Shell uint8
+
+const (
+	ShellPosix      Shell = iota // ShellPosix covers sh, bash, zsh and friends.
+	ShellCmd                     // ShellCmd is Windows' cmd.exe, at an interactive prompt or via "cmd /c".
+	ShellPowerShell              // ShellPowerShell is Windows PowerShell or pwsh.
+	ShellBatch                   // ShellBatch is cmd.exe running a .bat or .cmd file.
+)
+
+func (s Shell) String() string {
+	switch s {
+	case ShellPosix:
+		return "sh"
+	case ShellCmd:
+		return "cmd"
+	case ShellPowerShell:
+		return "powershell"
+	case ShellBatch:
+		return "bat"
+	default:
+		return "unknown"
+	}
+}
+
+// QuoteFor returns the piece as a single, correctly escaped argument for the given
+// shell. cmd.exe is one of the places that really does need native separators, so
+// for ShellCmd and ShellBatch the path is given backslashes regardless of the
+// platform we're on; the other shells get the posix form.
+//
+// This is entirely lexical, so you can generate scripts for one platform on another.
+// cmd.exe escapes '%' differently in a batch file than at the prompt, so use
+// ShellBatch when writing .bat or .cmd files.
+func QuoteFor(shell Shell, piece Piecer) string {
+	str := piece.Piece().String()
+	if shell == ShellCmd || shell == ShellBatch {
+		str = strings.ReplaceAll(str, "/", `\`)
+	}
+	return QuoteArgFor(shell, str)
+}
+
+// QuoteArgFor escapes an arbitrary string as a single argument for the given shell,
+// without any separator conversion.
+func QuoteArgFor(shell Shell, arg string) string {
+	switch shell {
+	case ShellCmd:
+		return quoteCmd(arg, false)
+	case ShellBatch:
+		return quoteCmd(arg, true)
+	case ShellPowerShell:
+		return quotePowerShell(arg)
+	default:
+		return quotePosix(arg)
+	}
+}
+
+// isShellSafe reports whether every byte of str is an ASCII letter, digit, or in extra.
+func isShellSafe(str string, extra string) bool {
+	for idx := 0; idx < len(str); idx++ {
+		c := str[idx]
+		if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (c < '0' || c > '9') && strings.IndexByte(extra, c) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// quotePosix leaves harmless strings alone and single-quotes everything else, which
+// sh won't interpret at all, other than to end the quoting at the next single quote.
+func quotePosix(arg string) string {
+	if arg != "" && isShellSafe(arg, "_@%+=:,./-") {
+		return arg
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+// powerShellQuotes lists the characters PowerShell treats as single quotes, which
+// includes the typographic ones.
+const powerShellQuotes = "'‘’‚‛"
+
+// quotePowerShell single-quotes the argument, within which PowerShell only
+// interprets a quote character, escaped by doubling it. Commas aren't safe bare,
+// since they make an array.
+func quotePowerShell(arg string) string {
+	if arg != "" && arg[0] != '-' && isShellSafe(arg, `_./:\-`) {
+		return arg
+	}
+	var buf strings.Builder
+	buf.Grow(len(arg) + 2)
+	buf.WriteByte('\'')
+	for _, r := range arg {
+		if strings.ContainsRune(powerShellQuotes, r) {
+			buf.WriteRune(r)
+		}
+		buf.WriteRune(r)
+	}
+	buf.WriteByte('\'')
+	return buf.String()
+}
+
+// cmdMetaChars are the characters cmd.exe will act on unless they're ^-escaped.
+const cmdMetaChars = `()%!^"<>&|`
+
+// quoteCmd first quotes the argument so the target program's CommandLineToArgvW
+// sees one argument, then ^-escapes every character cmd.exe would otherwise act on,
+// including the quotes. Escaping everything means cmd's own idea of whether we're
+// inside quotes never matters; in particular "%" can't be used to expand variables.
+// The exception is "%" in a batch file, where "^%" doesn't work and it has to be
+// doubled instead.
+func quoteCmd(arg string, batch bool) string {
+	quoted := QuoteWindowsArg(arg)
+	if !strings.ContainsAny(quoted, cmdMetaChars) {
+		return quoted
+	}
+	var buf strings.Builder
+	buf.Grow(len(quoted) * 2)
+	for idx := 0; idx < len(quoted); idx++ {
+		switch c := quoted[idx]; {
+		case c == '%' && batch:
+			buf.WriteByte('%')
+		case strings.IndexByte(cmdMetaChars, c) >= 0:
+			buf.WriteByte('^')
+		}
+		buf.WriteByte(quoted[idx])
+	}
+	return buf.String()
+}
+
+// QuoteWindowsArg quotes a single argument so that a Windows program parsing its
+// command line with CommandLineToArgvW (or the MSVC runtime) sees exactly arg:
+// backslashes are only special when they precede a double-quote, in which case
+// they and the quote must be escaped.
+func QuoteWindowsArg(arg string) string {
+	if arg != "" && !strings.ContainsAny(arg, " \t\n\v\"") {
+		return arg
+	}
+	var buf strings.Builder
+	buf.Grow(len(arg) + 2)
+	buf.WriteByte('"')
+	for idx := 0; idx < len(arg); idx++ {
+		backslashes := 0
+		for ; idx < len(arg) && arg[idx] == '\\'; idx++ {
+			backslashes++
+		}
+		switch {
+		case idx == len(arg):
+			// We're about to write the closing quote, so all of these need escaping.
+			buf.WriteString(strings.Repeat(`\`, backslashes*2))
+		case arg[idx] == '"':
+			buf.WriteString(strings.Repeat(`\`, backslashes*2+1))
+			buf.WriteByte('"')
+		default:
+			buf.WriteString(strings.Repeat(`\`, backslashes))
+			buf.WriteByte(arg[idx])
+		}
+	}
+	buf.WriteByte('"')
+	return buf.String()
+}
+
+// WindowsCommandLine builds a command line from an argv, quoting each argument with
+// QuoteWindowsArg, suitable for CreateProcess. It is not safe to hand the result to
+// cmd.exe; use QuoteArgFor(ShellCmd, ...) for each argument instead.
+func WindowsCommandLine(args ...string) string {
+	quoted := make([]string, len(args))
+	for idx, arg := range args {
+		quoted[idx] = QuoteWindowsArg(arg)
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,142 @@
+package apathy
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// splitWindowsArgs is a straightforward implementation of the CommandLineToArgvW
+// rules for everything but argv[0], to check QuoteWindowsArg against.
+func splitWindowsArgs(cmdline string) []string {
+	var args []string
+	var arg strings.Builder
+	inArg, inQuotes := false, false
+	for idx := 0; idx < len(cmdline); idx++ {
+		c := cmdline[idx]
+		switch {
+		case c == '\\':
+			backslashes := 0
+			for ; idx < len(cmdline) && cmdline[idx] == '\\'; idx++ {
+				backslashes++
+			}
+			if idx < len(cmdline) && cmdline[idx] == '"' {
+				arg.WriteString(strings.Repeat(`\`, backslashes/2))
+				if backslashes%2 == 1 {
+					arg.WriteByte('"')
+				} else {
+					inQuotes = !inQuotes
+				}
+			} else {
+				arg.WriteString(strings.Repeat(`\`, backslashes))
+				idx--
+			}
+			inArg = true
+		case c == '"':
+			inQuotes = !inQuotes
+			inArg = true
+		case (c == ' ' || c == '\t') && !inQuotes:
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteByte(c)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	return args
+}
+
+func TestQuoteWindowsArg(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct{ input, expected string }{
+		{"", `""`},
+		{"simple", "simple"},
+		{`c:\no\spaces`, `c:\no\spaces`},
+		{`c:\program files\`, `"c:\program files\\"`},
+		{`say "hi"`, `"say \"hi\""`},
+		{`back\"slash`, `"back\\\"slash"`},
+		{"tab\there", "\"tab\there\""},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			assert.Equal(t, tc.expected, QuoteWindowsArg(tc.input))
+		})
+	}
+}
+
+func TestWindowsCommandLine(t *testing.T) {
+	t.Parallel()
+
+	args := []string{`converter.exe`, `c:\program files\`, ``, `a "quoted" word`, `\\server\share\`, `\"`, `trailing\\`, `100%`}
+	cmdline := WindowsCommandLine(args...)
+	assert.Equal(t, args, splitWindowsArgs(cmdline))
+}
+
+func TestQuoteFor(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name                   string
+		piece                  APiece
+		posix, cmd, powershell string
+	}{
+		{"plain", "c:/tools/bin", "c:/tools/bin", `c:\tools\bin`, "c:/tools/bin"},
+		{"empty", "", "''", `^"^"`, "''"},
+		{"spaces", "/my files/a b", "'/my files/a b'", `^"\my files\a b^"`, "'/my files/a b'"},
+		{"quote", "/it's", `'/it'\''s'`, `\it's`, "'/it''s'"},
+		{"typographic quote", "/it’s", "'/it’s'", `\it’s`, "'/it’’s'"},
+		{"percent", "c:/%PATH%", "c:/%PATH%", `c:\^%PATH^%`, "'c:/%PATH%'"},
+		{"caret and amp", "c:/a^b&c", "'c:/a^b&c'", `c:\a^^b^&c`, "'c:/a^b&c'"},
+		{"spaces and percent", "c:/100 % done", "'c:/100 % done'", `^"c:\100 ^% done^"`, "'c:/100 % done'"},
+		{"dollar", "/$HOME", "'/$HOME'", `\$HOME`, "'/$HOME'"},
+		{"dash", "-rf", "-rf", "-rf", "'-rf'"},
+		{"comma", "/a,b", "/a,b", `\a,b`, "'/a,b'"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.posix, QuoteFor(ShellPosix, tc.piece), "posix")
+			assert.Equal(t, tc.cmd, QuoteFor(ShellCmd, tc.piece), "cmd")
+			assert.Equal(t, tc.powershell, QuoteFor(ShellPowerShell, tc.piece), "powershell")
+		})
+	}
+}
+
+func TestQuoteFor_Batch(t *testing.T) {
+	t.Parallel()
+
+	// The same as cmd, except that in a batch file "%" is escaped by doubling it.
+	for piece, expected := range map[APiece]string{
+		"c:/tools/bin":  `c:\tools\bin`,
+		"c:/%PATH%":     `c:\%%PATH%%`,
+		"c:/100 % done": `^"c:\100 %% done^"`,
+		"c:/a^b&c":      `c:\a^^b^&c`,
+		"c:/50%/a b(1)": `^"c:\50%%\a b^(1^)^"`,
+	} {
+		assert.Equal(t, expected, QuoteFor(ShellBatch, piece), piece)
+	}
+}
+
+func TestQuoteArgFor(t *testing.T) {
+	t.Parallel()
+
+	// No separator conversion for non-path arguments.
+	assert.Equal(t, `/switch`, QuoteArgFor(ShellCmd, "/switch"))
+	assert.Equal(t, `^"a b^"`, QuoteArgFor(ShellCmd, "a b"))
+	assert.Equal(t, `'a b'`, QuoteArgFor(Shell(99), "a b"))
+}
+
+func TestShell_String(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "sh", ShellPosix.String())
+	assert.Equal(t, "cmd", ShellCmd.String())
+	assert.Equal(t, "powershell", ShellPowerShell.String())
+	assert.Equal(t, "bat", ShellBatch.String())
+	assert.Equal(t, "unknown", Shell(99).String())
+}
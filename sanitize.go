@@ -0,0 +1,139 @@
+package apathy
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// SanitizeMode selects how Sanitize rewrites names a platform won't accept.
+type SanitizeMode uint8
+
+const (
+	// SanitizeReplace replaces offending characters with '_', and appends '_' to
+	// reserved names. It's readable, but different names can end up the same.
+	SanitizeReplace SanitizeMode = iota
+	// SanitizeEscape percent-encodes offending bytes, including '%' itself, so
+	// distinct names stay distinct and Unsanitize can recover the original.
+	SanitizeEscape
+)
+
+// Sanitize rewrites each name in the piece so that it is acceptable to the target
+// platform, using SanitizeReplace. See SanitizeWith.
+func Sanitize(piece Piecer, target Platform) APiece {
+	return SanitizeWith(piece, target, SanitizeReplace)
+}
+
+// SanitizeWith rewrites the names in the piece that Validate would object to for
+// their characters, reserved names, trailing dots and spaces, or invalid UTF-8.
+// Length violations can't be fixed without losing information, so they're left
+// alone. A leading UNC share, or drive letter on Windows, is not touched. An
+// unknown target leaves the piece as it is; Validate reports it.
+func SanitizeWith(piece Piecer, target Platform, mode SanitizeMode) APiece {
+	rules, err := target.rules()
+	if err != nil {
+		return piece.Piece()
+	}
+	str := piece.Piece().String()
+	volume := volumeLen(APiece(str), target)
+
+	var buf strings.Builder
+	buf.Grow(len(str))
+	buf.WriteString(str[:volume])
+	for idx, component := range strings.Split(str[volume:], "/") {
+		if idx > 0 {
+			buf.WriteByte('/')
+		}
+		if component == "." || component == ".." {
+			buf.WriteString(component)
+			continue
+		}
+		sanitizeName(&buf, component, rules, mode)
+	}
+	return APiece(buf.String())
+}
+
+func sanitizeName(buf *strings.Builder, name string, rules platformRules, mode SanitizeMode) {
+	reserved := rules.reservedNames && isWindowsReservedName(name)
+	trailing := len(name)
+	if rules.trailingDots {
+		trailing = len(strings.TrimRight(name, ". "))
+	}
+
+	for idx := 0; idx < len(name); {
+		c := name[idx]
+		size := 1
+		escape := false
+		switch {
+		case c == '%':
+			escape = mode == SanitizeEscape
+		case (rules.controlChars && c < 0x20) || strings.IndexByte(rules.forbidden, c) >= 0:
+			escape = true
+		case idx >= trailing:
+			escape = true
+		case reserved && idx == 0:
+			escape = mode == SanitizeEscape
+		case c >= utf8.RuneSelf:
+			r, runeSize := utf8.DecodeRuneInString(name[idx:])
+			size = runeSize
+			escape = rules.requireUTF8 && r == utf8.RuneError && runeSize == 1
+		}
+
+		switch {
+		case !escape:
+			buf.WriteString(name[idx : idx+size])
+		case mode == SanitizeEscape:
+			fmt.Fprintf(buf, "%%%02X", c)
+		default:
+			buf.WriteByte('_')
+		}
+		idx += size
+
+		// Reserved names get their suffix before any extension.
+		if reserved && mode == SanitizeReplace && (idx == len(name) || name[idx] == '.') {
+			buf.WriteByte('_')
+			reserved = false
+		}
+	}
+}
+
+// Unsanitize reverses SanitizeWith(..., SanitizeEscape), decoding the %XX escapes.
+// A malformed escape, or one that decodes to a separator, is an ErrBadSyntax.
+func Unsanitize(piece Piecer) (APiece, error) {
+	str := piece.Piece().String()
+	if !strings.Contains(str, "%") {
+		return piece.Piece(), nil
+	}
+	var buf strings.Builder
+	buf.Grow(len(str))
+	for idx := 0; idx < len(str); idx++ {
+		if str[idx] != '%' {
+			buf.WriteByte(str[idx])
+			continue
+		}
+		if idx+2 >= len(str) {
+			return "", &PathError{Op: "Unsanitize", Piece: piece.Piece(), Err: fmt.Errorf("%w: truncated escape", ErrBadSyntax)}
+		}
+		hi, okHi := unhex(str[idx+1])
+		lo, okLo := unhex(str[idx+2])
+		decoded := hi<<4 | lo
+		if !okHi || !okLo || decoded == '/' || decoded == '\\' {
+			return "", &PathError{Op: "Unsanitize", Piece: piece.Piece(), Err: fmt.Errorf("%w: bad escape %q", ErrBadSyntax, str[idx:idx+3])}
+		}
+		buf.WriteByte(decoded)
+		idx += 2
+	}
+	return APiece(buf.String()), nil
+}
+
+func unhex(c byte) (byte, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0', true
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10, true
+	case c >= 'A' && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
@@ -0,0 +1,74 @@
+package apathy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSanitize(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		input            APiece
+		platform         Platform
+		replace, escaped APiece
+	}{
+		{"assets/hero.png", PlatformWindows, "assets/hero.png", "assets/hero.png"},
+		{"C:/what?/a<b>.txt", PlatformWindows, "C:/what_/a_b_.txt", "C:/what%3F/a%3Cb%3E.txt"},
+		{"//server/share/x:y", PlatformWindows, "//server/share/x_y", "//server/share/x%3Ay"},
+		{"a/CON", PlatformWindows, "a/CON_", "a/%43ON"},
+		{"nul.tar.gz", PlatformWindows, "nul_.tar.gz", "%6Eul.tar.gz"},
+		{"dots.../end ", PlatformWindows, "dots___/end_", "dots%2E%2E%2E/end%20"},
+		{"100%", PlatformWindows, "100%", "100%25"},
+		{"tab\tname", PlatformWindows, "tab_name", "tab%09name"},
+		{"bad\xffutf", PlatformWindows, "bad_utf", "bad%FFutf"},
+		{"héllo", PlatformWindows, "héllo", "héllo"},
+		{"../up", PlatformWindows, "../up", "../up"},
+		{"a:b/CON", PlatformDarwin, "a_b/CON", "a%3Ab/CON"},
+		{"a:b/CON\xff", PlatformLinux, "a:b/CON\xff", "a:b/CON\xff"},
+	} {
+		t.Run(string(tc.input), func(t *testing.T) {
+			replaced := Sanitize(tc.input, tc.platform)
+			assert.Equal(t, tc.replace, replaced, "replace")
+			assert.Empty(t, Validate(replaced, tc.platform))
+
+			escaped := SanitizeWith(tc.input, tc.platform, SanitizeEscape)
+			assert.Equal(t, tc.escaped, escaped, "escape")
+			assert.Empty(t, Validate(escaped, tc.platform))
+
+			original, err := Unsanitize(escaped)
+			require.NoError(t, err)
+			assert.Equal(t, tc.input, original)
+		})
+	}
+}
+
+func TestSanitize_KeepsDistinctNamesDistinct(t *testing.T) {
+	t.Parallel()
+
+	// These all collapse to the same thing with SanitizeReplace.
+	names := []APiece{"a?b", "a*b", "a_b", "a%3Fb", "a%2Ab"}
+	seen := map[APiece]APiece{}
+	for _, name := range names {
+		escaped := SanitizeWith(name, PlatformWindows, SanitizeEscape)
+		if prior, ok := seen[escaped]; ok {
+			t.Errorf("%q and %q both escape to %q", prior, name, escaped)
+		}
+		seen[escaped] = name
+	}
+	assert.Equal(t, Sanitize(names[0], PlatformWindows), Sanitize(names[1], PlatformWindows))
+}
+
+func TestUnsanitize_Errors(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []APiece{"a%", "a%4", "a%zz", "a%2Fb", "a%5cb"} {
+		_, err := Unsanitize(input)
+		assert.ErrorIs(t, err, ErrBadSyntax, input.String())
+	}
+	piece, err := Unsanitize(APiece("no/escapes"))
+	assert.NoError(t, err)
+	assert.Equal(t, APiece("no/escapes"), piece)
+}
@@ -0,0 +1,145 @@
+package apathy
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// maxSymlinks is how many links SecureJoin will follow before deciding it's
+// being sent round in circles, matching the usual Linux limit.
+const maxSymlinks = 40
+
+// SecureJoinOptions adjusts how SecureJoinWith treats hostile input.
+type SecureJoinOptions struct {
+	// Clamp treats the root as if it were the filesystem root: ".." at the root
+	// stays there, and absolute paths, drive letters and UNC prefixes are taken
+	// as relative to the root. Without it, those are errors.
+	Clamp bool
+	// FollowSymlinks resolves symlinks inside the root as they're encountered, so
+	// that a link can't be used to escape it either. Absolute link targets are
+	// accepted if they point within the root, and are otherwise errors unless
+	// Clamp is set, in which case they're relative to the root.
+	FollowSymlinks bool
+}
+
+// SecureJoin joins an untrusted path, such as an archive entry or manifest line,
+// onto root, and guarantees the result is inside root. Any attempt to escape,
+// through "..", an absolute path, a drive letter or a UNC prefix, is an error
+// wrapping ErrEscapesRoot or ErrUnsafePath. See SecureJoinWith for options.
+func SecureJoin(root APath, untrusted string) (APiece, error) {
+	return SecureJoinWith(root, untrusted, SecureJoinOptions{})
+}
+
+// SecureJoinWith is SecureJoin with options.
+func SecureJoinWith(root APath, untrusted string, opts SecureJoinOptions) (APiece, error) {
+	rootPiece := root.Piece()
+	fail := func(err error) (APiece, error) {
+		return "", &PathError{Op: "SecureJoin", Piece: NewAPiece(untrusted), Err: err}
+	}
+	if strings.IndexByte(untrusted, 0) >= 0 {
+		return fail(fmt.Errorf("%w: contains NUL", ErrUnsafePath))
+	}
+
+	relative, hadVolume := stripVolume(ToSlash(untrusted))
+	if hadVolume && !opts.Clamp {
+		return fail(fmt.Errorf("%w: not relative", ErrUnsafePath))
+	}
+
+	remaining := strings.Split(relative, "/")
+	var resolved []string
+	links := 0
+	checkLinks := opts.FollowSymlinks
+	// missingDepth is how many components were resolved when we hit one that
+	// doesn't exist; once ".." takes us back above it, we're somewhere real again.
+	missingDepth := 0
+	for len(remaining) > 0 {
+		component := remaining[0]
+		remaining = remaining[1:]
+		switch component {
+		case "", ".":
+			continue
+		case "..":
+			if len(resolved) > 0 {
+				resolved = resolved[:len(resolved)-1]
+			} else if !opts.Clamp {
+				return fail(ErrEscapesRoot)
+			}
+			if opts.FollowSymlinks && len(resolved) < missingDepth {
+				checkLinks = true
+			}
+			continue
+		}
+		resolved = append(resolved, component)
+		if !checkLinks {
+			continue
+		}
+
+		current := Join(rootPiece, APiece(strings.Join(resolved, "/")))
+		info, err := Lstat(current.String())
+		if err != nil {
+			if !os.IsNotExist(err) {
+				return fail(err)
+			}
+			// Nothing below a missing item can be a link.
+			checkLinks = false
+			missingDepth = len(resolved)
+			continue
+		}
+		if info.Mode()&fs.ModeSymlink == 0 {
+			continue
+		}
+		if links++; links > maxSymlinks {
+			return fail(ErrTooManyLinks)
+		}
+		target, err := Readlink(current.String())
+		if err != nil {
+			return fail(err)
+		}
+		// Replace the link with its target, and carry on from there.
+		resolved = resolved[:len(resolved)-1]
+		target = ToSlash(target)
+		if relTarget, isAbs := stripVolume(target); isAbs {
+			inside, ok := trimBase(rootPiece, NewAPiece(target))
+			switch {
+			case ok:
+				relTarget = inside.String()
+			case !opts.Clamp:
+				return fail(fmt.Errorf("%w: via symlink %s", ErrEscapesRoot, current))
+			}
+			target = relTarget
+			resolved = resolved[:0]
+		}
+		remaining = append(strings.Split(target, "/"), remaining...)
+	}
+	return Join(rootPiece, APiece(strings.Join(resolved, "/"))), nil
+}
+
+// stripVolume removes any UNC, drive letter or root prefix from a slashed path,
+// reporting whether there was one.
+func stripVolume(str string) (string, bool) {
+	original := str
+	if share := uncShareRoot(APiece(str)); share != "" {
+		str = str[share.Len():]
+	} else if hasDriveLetter(APiece(str)) {
+		str = str[WindowsDriveLen:]
+	}
+	str = strings.TrimLeft(str, "/")
+	return str, len(str) != len(original)
+}
+
+// trimBase returns the part of p below base, if p is base or is inside it.
+func trimBase(base, p APiece) (APiece, bool) {
+	if p == base {
+		return "", true
+	}
+	prefix := base.String()
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	if !strings.HasPrefix(p.String(), prefix) {
+		return "", false
+	}
+	return p[len(prefix):], true
+}
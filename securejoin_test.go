@@ -0,0 +1,137 @@
+package apathy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecureJoin(t *testing.T) {
+	t.Parallel()
+
+	root := &aPath{APiece: "/srv/extract", aType: ATypeDir}
+	for _, tc := range []struct {
+		input    string
+		expected APiece
+		err      error
+	}{
+		{"", "/srv/extract", nil},
+		{"a/b.txt", "/srv/extract/a/b.txt", nil},
+		{`a\b\..\c.txt`, "/srv/extract/a/c.txt", nil},
+		{"a/../../b", "", ErrEscapesRoot},
+		{"..", "", ErrEscapesRoot},
+		{"a/./../b/", "/srv/extract/b", nil},
+		{"/etc/passwd", "", ErrUnsafePath},
+		{`\etc\passwd`, "", ErrUnsafePath},
+		{"C:/Windows", "", ErrUnsafePath},
+		{"c:relative", "", ErrUnsafePath},
+		{`\\server\share\x`, "", ErrUnsafePath},
+		{"a\x00b", "", ErrUnsafePath},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			result, err := SecureJoin(root, tc.input)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				assert.Empty(t, result)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+
+	// Joining onto "/" mustn't produce a UNC path.
+	result, err := SecureJoin(&aPath{APiece: "/", aType: ATypeDir}, "etc/passwd")
+	require.NoError(t, err)
+	assert.Equal(t, APiece("/etc/passwd"), result)
+}
+
+func TestSecureJoinWith_Clamp(t *testing.T) {
+	t.Parallel()
+
+	root := &aPath{APiece: "c:/extract", aType: ATypeDir}
+	clamp := SecureJoinOptions{Clamp: true}
+	for _, tc := range []struct {
+		input    string
+		expected APiece
+	}{
+		{"a/../../b", "c:/extract/b"},
+		{"../../../etc/passwd", "c:/extract/etc/passwd"},
+		{"/etc/passwd", "c:/extract/etc/passwd"},
+		{`D:\Windows\..\x`, "c:/extract/x"},
+		{`\\server\share\x`, "c:/extract/x"},
+		{"//", "c:/extract"},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			result, err := SecureJoinWith(root, tc.input, clamp)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+
+	_, err := SecureJoinWith(root, "nul\x00", clamp)
+	assert.ErrorIs(t, err, ErrUnsafePath)
+}
+
+func TestSecureJoinWith_Symlinks(t *testing.T) {
+	t.Parallel()
+	if onWindows {
+		t.Skip("symlinks need privileges on windows")
+	}
+
+	dir := t.TempDir()
+	rootDir := filepath.Join(dir, "root")
+	require.NoError(t, os.MkdirAll(filepath.Join(rootDir, "real", "deeper"), 0755))
+	symlink := func(target, name string) {
+		require.NoError(t, os.Symlink(target, filepath.Join(rootDir, name)))
+	}
+	symlink("real/deeper", "inside")
+	symlink("../..", "real/up")
+	symlink(dir, "outside")
+	symlink(filepath.Join(rootDir, "real"), "absinside")
+	symlink("/etc", "etc") // which, clamped, points to itself
+	symlink("/etc", "link")
+	symlink("/real", "sys") // and clamped, this points to root/real
+	symlink("loop2", "loop1")
+	symlink("loop1", "loop2")
+	rootPiece := NewAPiece(rootDir)
+	root := MustNewAPath(rootPiece)
+
+	follow := SecureJoinOptions{FollowSymlinks: true}
+	clampFollow := SecureJoinOptions{FollowSymlinks: true, Clamp: true}
+	for _, tc := range []struct {
+		name     string
+		input    string
+		opts     SecureJoinOptions
+		expected APiece
+		err      error
+	}{
+		{"lexical ignores links", "outside/x", SecureJoinOptions{}, Join(rootPiece, "outside/x"), nil},
+		{"relative link", "inside/file", follow, Join(rootPiece, "real/deeper/file"), nil},
+		{"dotdot after link", "inside/../x", follow, Join(rootPiece, "real/x"), nil},
+		{"link escapes", "real/up/x", follow, "", ErrEscapesRoot},
+		{"link clamped", "real/up/x", clampFollow, Join(rootPiece, "x"), nil},
+		{"absolute link outside", "outside", follow, "", ErrEscapesRoot},
+		{"absolute link inside", "absinside/deeper", follow, Join(rootPiece, "real/deeper"), nil},
+		{"absolute link clamped", "sys/deeper", clampFollow, Join(rootPiece, "real/deeper"), nil},
+		{"absolute link clamped loop", "etc/passwd", clampFollow, "", ErrTooManyLinks},
+		{"missing", "nothing/here", follow, Join(rootPiece, "nothing/here"), nil},
+		{"missing then dotdot", "missing/../link/passwd", follow, "", ErrEscapesRoot},
+		{"missing then deeper dotdot", "real/missing/x/../../../link/passwd", follow, "", ErrEscapesRoot},
+		{"missing then dotdot inside", "missing/x/../y", follow, Join(rootPiece, "missing/y"), nil},
+		{"loop", "loop1", follow, "", ErrTooManyLinks},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := SecureJoinWith(root, tc.input, tc.opts)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package apathy
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"time"
+)
+
+// Scan implements sql.Scanner. The column value goes through NewAPiece, so unclean
+// strings in the database don't leak back into the pipeline.
+func (p *APiece) Scan(src any) error {
+	switch value := src.(type) {
+	case string:
+		*p = NewAPiece(value)
+	case []byte:
+		*p = NewAPiece(string(value))
+	default:
+		return fmt.Errorf("%w: %T into APiece", ErrCannotScan, src)
+	}
+	return nil
+}
+
+// Value implements driver.Valuer, storing the posix form of the piece.
+func (p APiece) Value() (driver.Value, error) {
+	return string(p), nil
+}
+
+// Scan implements sql.Scanner, accepting either the name of the type or its
+// numeric value.
+func (a *APathType) Scan(src any) error {
+	switch value := src.(type) {
+	case string:
+		return a.UnmarshalText([]byte(value))
+	case []byte:
+		return a.UnmarshalText(value)
+	case int64:
+		if value < 0 || value >= int64(numAPathTypes) {
+			return fmt.Errorf("%w: %d", ErrUnknownAPathType, value)
+		}
+		*a = APathType(value)
+		return nil
+	default:
+		return fmt.Errorf("%w: %T into APathType", ErrCannotScan, src)
+	}
+}
+
+// Value implements driver.Valuer, storing the name of the type.
+func (a APathType) Value() (driver.Value, error) {
+	return a.String(), nil
+}
+
+// RowScanner is satisfied by *sql.Row and *sql.Rows.
+type RowScanner interface {
+	Scan(dest ...any) error
+}
+
+// ScanAPath reads a row of path, type, mtime and size columns, in that order, and
+// rebuilds the APath they describe without re-stating it. The mtime may be stored
+// as a time, or as an integer count of nanoseconds since the unix epoch; mtime and
+// size may be NULL for paths that don't exist.
+func ScanAPath(row RowScanner) (APath, error) {
+	var (
+		piece APiece
+		aType APathType
+		mtime mtimeScanner
+		size  sql.NullInt64
+	)
+	if err := row.Scan(&piece, &aType, &mtime, &size); err != nil {
+		return nil, err
+	}
+	if !piece.IsAbs() {
+		return nil, &PathError{Op: "ScanAPath", Piece: piece, Err: ErrNotAbsolute}
+	}
+	return &aPath{APiece: piece, aType: aType, mtime: time.Time(mtime), size: size.Int64}, nil
+}
+
+// APathValues returns the path, type, mtime and size of an APath as arguments
+// for an INSERT or UPDATE, in the column order ScanAPath expects. The mtime is
+// NULL if the APath didn't exist.
+func APathValues(p APath) []any {
+	var mtime any
+	if !p.ModTime().IsZero() {
+		mtime = p.ModTime()
+	}
+	return []any{p.Piece(), p.Type(), mtime, p.Size()}
+}
+
+// mtimeScanner accepts the various ways drivers hand back a timestamp.
+type mtimeScanner time.Time
+
+func (m *mtimeScanner) Scan(src any) error {
+	switch value := src.(type) {
+	case nil:
+		*m = mtimeScanner{}
+	case time.Time:
+		*m = mtimeScanner(value)
+	case int64:
+		*m = mtimeScanner(time.Unix(0, value))
+	case string:
+		return m.parse(value)
+	case []byte:
+		return m.parse(string(value))
+	default:
+		return fmt.Errorf("%w: %T into mtime", ErrCannotScan, src)
+	}
+	return nil
+}
+
+func (m *mtimeScanner) parse(value string) error {
+	parsed, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrCannotScan, err)
+	}
+	*m = mtimeScanner(parsed)
+	return nil
+}
@@ -0,0 +1,138 @@
+package apathy
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRow hands its values to the destinations' Scan methods, as database/sql would.
+type fakeRow []any
+
+func (r fakeRow) Scan(dest ...any) error {
+	for idx, target := range dest {
+		if err := target.(sql.Scanner).Scan(r[idx]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestAPiece_Scan(t *testing.T) {
+	t.Parallel()
+
+	var piece APiece
+	assert.NoError(t, piece.Scan(`c:\windows\..\temp`))
+	assert.Equal(t, APiece("c:/temp"), piece)
+	assert.NoError(t, piece.Scan([]byte("a//b/./c")))
+	assert.Equal(t, APiece("a/b/c"), piece)
+	assert.ErrorIs(t, piece.Scan(nil), ErrCannotScan)
+	assert.ErrorIs(t, piece.Scan(int64(1)), ErrCannotScan)
+
+	value, err := APiece("/x/y").Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "/x/y", value)
+}
+
+func TestAPathType_Scan(t *testing.T) {
+	t.Parallel()
+
+	var aType APathType
+	assert.NoError(t, aType.Scan("Dir"))
+	assert.Equal(t, ATypeDir, aType)
+	assert.NoError(t, aType.Scan([]byte("Symlink")))
+	assert.Equal(t, ATypeSymlink, aType)
+	assert.NoError(t, aType.Scan(int64(ATypeFile)))
+	assert.Equal(t, ATypeFile, aType)
+	assert.ErrorIs(t, aType.Scan(int64(-1)), ErrUnknownAPathType)
+	assert.ErrorIs(t, aType.Scan("Teapot"), ErrUnknownAPathType)
+	assert.ErrorIs(t, aType.Scan(3.5), ErrCannotScan)
+
+	value, err := ATypeFile.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "File", value)
+}
+
+func TestScanAPath(t *testing.T) {
+	t.Parallel()
+
+	mtime := time.Date(2025, 2, 5, 12, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		name  string
+		row   fakeRow
+		piece APiece
+		aType APathType
+		mtime time.Time
+		size  int64
+	}{
+		{"time", fakeRow{"/a/b", "File", mtime, int64(10)}, "/a/b", ATypeFile, mtime, 10},
+		{"nanos", fakeRow{[]byte("/a/c"), int64(ATypeDir), mtime.UnixNano(), int64(0)}, "/a/c", ATypeDir, mtime, 0},
+		{"text", fakeRow{"/a/d", "File", mtime.Format(time.RFC3339Nano), int64(5)}, "/a/d", ATypeFile, mtime, 5},
+		{"nulls", fakeRow{`c:\gone\`, "NotExist", nil, nil}, "c:/gone", ANotExist, time.Time{}, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := ScanAPath(tc.row)
+			require.NoError(t, err)
+			assert.Equal(t, tc.piece, p.Piece())
+			assert.Equal(t, tc.aType, p.Type())
+			assert.True(t, tc.mtime.Equal(p.ModTime()))
+			assert.Equal(t, tc.size, p.Size())
+		})
+	}
+}
+
+func TestScanAPath_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := ScanAPath(fakeRow{"relative", "File", nil, nil})
+	assert.ErrorIs(t, err, ErrNotAbsolute)
+	_, err = ScanAPath(fakeRow{"/x", "File", "yesterday", nil})
+	assert.ErrorIs(t, err, ErrCannotScan)
+	_, err = ScanAPath(fakeRow{"/x", "File", 1.5, nil})
+	assert.ErrorIs(t, err, ErrCannotScan)
+
+	var rowErr = errors.New("no rows")
+	_, err = ScanAPath(failingRow{rowErr})
+	assert.ErrorIs(t, err, rowErr)
+}
+
+type failingRow struct{ err error }
+
+func (r failingRow) Scan(...any) error { return r.err }
+
+func TestAPathValues(t *testing.T) {
+	t.Parallel()
+
+	values := APathValues(&aPath{APiece: "/a", aType: ATypeFile, mtime: fixedTime, size: 3})
+	assert.Equal(t, []any{APiece("/a"), ATypeFile, fixedTime, int64(3)}, values)
+
+	values = APathValues(&aPath{APiece: "/gone"})
+	assert.Equal(t, []any{APiece("/gone"), ANotExist, nil, int64(0)}, values)
+
+	// And they round trip through ScanAPath, once converted the way a driver would.
+	for _, original := range []*aPath{
+		{APiece: "/a", aType: ATypeFile, mtime: fixedTime, size: 3},
+		{APiece: "/gone", aType: ANotExist},
+	} {
+		t.Run(original.String(), func(t *testing.T) {
+			var row fakeRow
+			for _, value := range APathValues(original) {
+				converted, err := driver.DefaultParameterConverter.ConvertValue(value)
+				require.NoError(t, err)
+				require.True(t, driver.IsValue(converted))
+				row = append(row, converted)
+			}
+			p, err := ScanAPath(row)
+			require.NoError(t, err)
+			assert.Equal(t, original.Piece(), p.Piece())
+			assert.Equal(t, original.Type(), p.Type())
+			assert.True(t, original.ModTime().Equal(p.ModTime()))
+			assert.Equal(t, original.Size(), p.Size())
+		})
+	}
+}
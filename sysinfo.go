@@ -0,0 +1,32 @@
+package apathy
+
+// FileID identifies a filesystem object by device and inode, so that two APaths
+// referring to the same underlying file (e.g. hard links) can be recognized.
+type FileID struct {
+	Dev uint64
+	Ino uint64
+}
+
+// sysInfo holds the platform-specific parts of an Lstat result.
+type sysInfo struct {
+	id       FileID
+	nlink    uint64
+	uid, gid int
+	valid    bool
+}
+
+// SameFile returns true if both APaths captured a FileID and the FileIDs match.
+// Unlike os.SameFile, this does not go back to the filesystem.
+func SameFile(a, b APath) bool {
+	aid, ok := a.(Identified)
+	if !ok {
+		return false
+	}
+	bid, ok := b.(Identified)
+	if !ok {
+		return false
+	}
+	aFileID, aOk := aid.FileID()
+	bFileID, bOk := bid.FileID()
+	return aOk && bOk && aFileID == bFileID
+}
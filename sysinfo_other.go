@@ -0,0 +1,11 @@
+//go:build !unix
+
+package apathy
+
+import "io/fs"
+
+// sysInfoOf: Lstat on this platform doesn't give us identity, link count or
+// ownership without opening the file, which we won't do behind your back.
+func sysInfoOf(fs.FileInfo) sysInfo {
+	return sysInfo{}
+}
@@ -0,0 +1,59 @@
+package apathy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSameFile_NoSysInfo(t *testing.T) {
+	t.Parallel()
+
+	// mockFileInfo has no Sys(), so there's no identity to compare.
+	a, err := NewAPathWith("/a", mockFileInfo{mode: 0644}, nil)
+	require.NoError(t, err)
+	b, err := NewAPathWith("/b", mockFileInfo{mode: 0644}, nil)
+	require.NoError(t, err)
+	_, ok := a.(Identified).FileID()
+	assert.False(t, ok)
+	assert.False(t, SameFile(a, b))
+	_, _, ok = a.(Owned).Owner()
+	assert.False(t, ok)
+	assert.Zero(t, a.(HardLinked).Nlink())
+	assert.Equal(t, os.FileMode(0644), a.(Permissioned).Perm())
+}
+
+func TestSameFile_HardLinks(t *testing.T) {
+	t.Parallel()
+	if onWindows {
+		t.Skip("lstat doesn't provide file identity on windows")
+	}
+
+	dir := t.TempDir()
+	original := filepath.Join(dir, "original")
+	require.NoError(t, os.WriteFile(original, []byte("data"), 0600))
+	require.NoError(t, os.Chmod(original, 0640))
+	require.NoError(t, os.Link(original, filepath.Join(dir, "linked")))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "other"), []byte("data"), 0600))
+
+	a, err := NewAPath(NewAPiece(dir), "original")
+	require.NoError(t, err)
+	b, err := NewAPath(NewAPiece(dir), "linked")
+	require.NoError(t, err)
+	c, err := NewAPath(NewAPiece(dir), "other")
+	require.NoError(t, err)
+
+	assert.True(t, SameFile(a, b))
+	assert.False(t, SameFile(a, c))
+	assert.Equal(t, uint64(2), a.(HardLinked).Nlink())
+	assert.Equal(t, uint64(1), c.(HardLinked).Nlink())
+	assert.Equal(t, os.FileMode(0640), a.(Permissioned).Perm())
+
+	uid, gid, ok := a.(Owned).Owner()
+	assert.True(t, ok)
+	assert.Equal(t, os.Getuid(), uid)
+	assert.Equal(t, os.Getgid(), gid)
+}
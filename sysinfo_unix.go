@@ -0,0 +1,24 @@
+//go:build unix
+
+package apathy
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// sysInfoOf extracts identity, link count and ownership from the Stat_t behind
+// the FileInfo, if there is one.
+func sysInfoOf(info fs.FileInfo) sysInfo {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok || stat == nil {
+		return sysInfo{}
+	}
+	return sysInfo{
+		id:    FileID{Dev: uint64(stat.Dev), Ino: uint64(stat.Ino)},
+		nlink: uint64(stat.Nlink),
+		uid:   int(stat.Uid),
+		gid:   int(stat.Gid),
+		valid: true,
+	}
+}
@@ -0,0 +1,127 @@
+package apathy
+
+import (
+	"io/fs"
+	"strings"
+	"sync"
+)
+
+// CaseResolver recovers the on-disk spelling of paths on filesystems that don't
+// care how you spell them, so that "c:/work/readme.MD" can be recorded as
+// "C:/Work/README.md". Directory listings are cached, so resolving many paths in
+// the same tree is cheap; use Forget if the tree changes underneath it. A
+// CaseResolver is safe for concurrent use.
+type CaseResolver struct {
+	collation Collation
+	mu        sync.Mutex
+	listings  map[APiece]*caseListing
+}
+
+// caseListing is a cached ReadDir of one directory.
+type caseListing struct {
+	exact  map[string]fs.DirEntry
+	folded map[APiece]fs.DirEntry // by Collation.Key, the first in lexical order
+}
+
+// NewCaseResolver returns a CaseResolver that matches names the way the given
+// collation does, e.g. NativePlatform.Collation(), or CollationFold for a
+// case-insensitive volume mounted on Linux.
+func NewCaseResolver(collation Collation) *CaseResolver {
+	return &CaseResolver{collation: collation, listings: map[APiece]*caseListing{}}
+}
+
+// TrueCase resolves a single path with a fresh CaseResolver that folds both case
+// and Unicode normalization; see CaseResolver.TrueCase.
+func TrueCase(p APath) (APath, error) {
+	return NewCaseResolver(CollationFoldNFD).TrueCase(p)
+}
+
+// TrueCase returns p spelled as each of its components is on disk. Each component
+// is looked up in its parent's listing, by exact name first and then by the
+// resolver's collation; if a case-sensitive directory has several matches, the
+// first in lexical order wins. The volume (drive letter or UNC share) is kept as
+// given. The result is built from the directory listing's information rather
+// than p's.
+//
+// If a component can't be found, the error wraps fs.ErrNotExist.
+func (r *CaseResolver) TrueCase(p APath) (APath, error) {
+	piece := p.Piece()
+	resolved := volumeRoot(piece)
+	if piece.Len() <= resolved.Len() {
+		return p, nil
+	}
+	rest := strings.TrimPrefix(piece.String()[resolved.Len():], "/")
+	if rest == "" {
+		return p, nil
+	}
+
+	var entry fs.DirEntry
+	for _, name := range strings.Split(rest, "/") {
+		listing, err := r.listing(resolved)
+		if err != nil {
+			return nil, err
+		}
+		if entry = listing.lookup(name, r.collation); entry == nil {
+			return nil, &PathError{Op: "TrueCase", Piece: piece, Err: fs.ErrNotExist}
+		}
+		resolved = Join(resolved, APiece(entry.Name()))
+	}
+	info, err := entry.Info()
+	return newAPathTolerant("TrueCase", resolved, info, err)
+}
+
+// Forget drops the cached listing of dir, which should be spelled as on disk, or
+// of every directory if dir is empty.
+func (r *CaseResolver) Forget(dir APiece) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if dir == "" {
+		r.listings = map[APiece]*caseListing{}
+	} else {
+		delete(r.listings, dir)
+	}
+}
+
+func (r *CaseResolver) listing(dir APiece) (*caseListing, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if listing, ok := r.listings[dir]; ok {
+		return listing, nil
+	}
+	entries, err := ReadDir(Normalize(dir))
+	if err != nil {
+		return nil, &PathError{Op: "ReadDir", Piece: dir, Err: err}
+	}
+	listing := &caseListing{
+		exact:  make(map[string]fs.DirEntry, len(entries)),
+		folded: make(map[APiece]fs.DirEntry, len(entries)),
+	}
+	for _, entry := range entries {
+		listing.exact[entry.Name()] = entry
+		key := r.collation.Key(APiece(entry.Name()))
+		if _, ok := listing.folded[key]; !ok {
+			listing.folded[key] = entry
+		}
+	}
+	r.listings[dir] = listing
+	return listing, nil
+}
+
+func (l *caseListing) lookup(name string, collation Collation) fs.DirEntry {
+	if entry, ok := l.exact[name]; ok {
+		return entry
+	}
+	return l.folded[collation.Key(APiece(name))]
+}
+
+// volumeRoot returns the part of an absolute piece that TrueCase doesn't look up:
+// "/", a drive root like "C:/", or on Windows a UNC share root like "//server/share".
+func volumeRoot(p APiece) APiece {
+	if share := hostUNCRoot(p); share != "" {
+		return share
+	}
+	if hasDriveLetter(p) {
+		return APiece(p.String()[:WindowsDriveLen] + "/")
+	}
+	return "/"
+}
@@ -0,0 +1,96 @@
+package apathy
+
+import (
+	"io/fs"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrueCase(t *testing.T) {
+	// Can't be parallel because it modifies globals.
+	dir := t.TempDir()
+	makeTree(t, dir, "Work/Textures/A.png", "Work/docs/README.md", "Work/caf\u00e9/x")
+	base := NewAPiece(dir)
+
+	var listed []string
+	realReadDir := ReadDir
+	defer withSaved(&ReadDir, func(name string) ([]os.DirEntry, error) {
+		listed = append(listed, name)
+		return realReadDir(name)
+	})()
+
+	resolver := NewCaseResolver(CollationFold)
+	typed := must(NewAPathTolerant(base, "work/textures/a.PNG"))
+	found, err := resolver.TrueCase(typed)
+	require.NoError(t, err)
+	assert.Equal(t, Join(base, "Work/Textures/A.png"), found.Piece())
+	assert.True(t, found.IsFile())
+	assert.EqualValues(t, len("Work/Textures/A.png"), found.Size())
+
+	// Directories already listed are cached.
+	listedBefore := len(listed)
+	found, err = resolver.TrueCase(must(NewAPathTolerant(base, "WORK/DOCS/readme.md")))
+	require.NoError(t, err)
+	assert.Equal(t, Join(base, "Work/docs/README.md"), found.Piece())
+	assert.Equal(t, []string{Normalize(Join(base, "Work/docs"))}, listed[listedBefore:])
+
+	// Forget drops the cache.
+	resolver.Forget(Join(base, "Work"))
+	listedBefore = len(listed)
+	_, err = resolver.TrueCase(must(NewAPathTolerant(base, "work/docs")))
+	require.NoError(t, err)
+	assert.Equal(t, []string{Normalize(Join(base, "Work"))}, listed[listedBefore:])
+
+	// A collation that doesn't fold case won't find it.
+	_, err = NewCaseResolver(CollationExact).TrueCase(typed)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	// TrueCase also folds normalization.
+	found, err = TrueCase(must(NewAPathTolerant(base, "WORK/CAFE\u0301/X")))
+	require.NoError(t, err)
+	assert.Equal(t, Join(base, "Work/caf\u00e9/x"), found.Piece())
+
+	_, err = TrueCase(must(NewAPathTolerant(base, "work/nope")))
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestTrueCase_PrefersExact(t *testing.T) {
+	t.Parallel()
+
+	dir := caseSensitiveTempDir(t)
+	makeTree(t, dir, "Readme", "README", "readme")
+	base := NewAPiece(dir)
+
+	for _, name := range []APiece{"Readme", "README", "readme"} {
+		found, err := TrueCase(must(NewAPathTolerant(base, name)))
+		require.NoError(t, err)
+		assert.Equal(t, Join(base, name), found.Piece())
+	}
+	// Otherwise the first in lexical order.
+	found, err := TrueCase(must(NewAPathTolerant(base, "ReadMe")))
+	require.NoError(t, err)
+	assert.Equal(t, Join(base, "README"), found.Piece())
+}
+
+func TestTrueCase_Roots(t *testing.T) {
+	t.Parallel()
+
+	roots := []APiece{"/", "C:/"}
+	if onWindows {
+		roots = append(roots, "//server/share")
+	}
+	for _, root := range roots {
+		p := &aPath{APiece: root, aType: ATypeDir}
+		found, err := TrueCase(p)
+		require.NoError(t, err)
+		assert.Same(t, p, found)
+	}
+	assert.Equal(t, APiece("C:/"), volumeRoot("C:/Windows"))
+	if onWindows {
+		assert.Equal(t, APiece("//server/share"), volumeRoot("//server/share/x"))
+	}
+	assert.Equal(t, APiece("/"), volumeRoot("/usr/bin"))
+}
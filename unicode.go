@@ -0,0 +1,203 @@
+package apathy
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// The normalization tables are generated from the same Unicode version as the Go
+// release's unicode package, which Collation's case folding uses, so that the two
+// agree on which characters exist; regenerate them when that moves on. Building
+// with an older Go means folding knows fewer characters than normalization does.
+//go:generate go run gen_unicode_tables.go -ucd ./ucd -version 17.0.0
+
+// UnicodeForm selects a Unicode normalization for names, so that the same name
+// typed or stored in different ways compares equal.
+type UnicodeForm uint8
+
+const (
+	UnicodeNone UnicodeForm = iota // UnicodeNone leaves names as they are.
+	UnicodeNFC                     // UnicodeNFC composes, e.g. "é" is U+00E9, as most Windows and Linux software does.
+	UnicodeNFD                     // UnicodeNFD decomposes, e.g. "é" is "e" and U+0301, as macOS does.
+)
+
+func (f UnicodeForm) String() string {
+	switch f {
+	case UnicodeNFC:
+		return "NFC"
+	case UnicodeNFD:
+		return "NFD"
+	default:
+		return "none"
+	}
+}
+
+// normalize applies the form to str.
+func (f UnicodeForm) normalize(str string) string {
+	switch f {
+	case UnicodeNFC:
+		return toNFC(str)
+	case UnicodeNFD:
+		return toNFD(str)
+	default:
+		return str
+	}
+}
+
+// NewAPieceNormalized is NewAPiece followed by NormalizeUnicode, so that names that
+// arrive decomposed from macOS and composed from elsewhere end up the same.
+func NewAPieceNormalized(str string, form UnicodeForm) APiece {
+	return NormalizeUnicode(NewAPiece(str), form)
+}
+
+// NormalizeUnicode puts each component of the piece into the given form. Bytes
+// that aren't valid UTF-8 are left as they are.
+func NormalizeUnicode(piece Piecer, form UnicodeForm) APiece {
+	// '/' is a starter that never composes, so components can't affect each
+	// other and we can do the whole piece at once.
+	return APiece(form.normalize(piece.Piece().String()))
+}
+
+// IsNormalized returns true if the piece is already in the given form, i.e.
+// NormalizeUnicode wouldn't change it. Everything is in UnicodeNone form, and
+// ASCII is in every form.
+func IsNormalized(piece Piecer, form UnicodeForm) bool {
+	str := piece.Piece().String()
+	return isASCII(str) || form.normalize(str) == str
+}
+
+// Hangul syllables decompose and compose algorithmically rather than via tables.
+const (
+	hangulSBase  = 0xAC00
+	hangulLBase  = 0x1100
+	hangulVBase  = 0x1161
+	hangulTBase  = 0x11A7
+	hangulLCount = 19
+	hangulVCount = 21
+	hangulTCount = 28
+	hangulNCount = hangulVCount * hangulTCount
+	hangulSCount = hangulLCount * hangulNCount
+)
+
+// toNFD returns the canonical decomposition of str. Bytes that aren't valid UTF-8
+// are passed through untouched and act as boundaries.
+func toNFD(str string) string {
+	return normalizeString(str, false)
+}
+
+// toNFC returns the canonical composition of str. Bytes that aren't valid UTF-8
+// are passed through untouched and act as boundaries.
+func toNFC(str string) string {
+	return normalizeString(str, true)
+}
+
+func normalizeString(str string, compose bool) string {
+	if isASCII(str) {
+		return str
+	}
+	var buf strings.Builder
+	buf.Grow(len(str))
+	var runes []rune
+	flush := func() {
+		runes = canonicalOrder(runes)
+		if compose {
+			runes = canonicalCompose(runes)
+		}
+		for _, r := range runes {
+			buf.WriteRune(r)
+		}
+		runes = runes[:0]
+	}
+	for idx := 0; idx < len(str); {
+		r, size := utf8.DecodeRuneInString(str[idx:])
+		if r == utf8.RuneError && size == 1 {
+			flush()
+			buf.WriteByte(str[idx])
+		} else {
+			runes = decomposeRune(runes, r)
+		}
+		idx += size
+	}
+	flush()
+	return buf.String()
+}
+
+func isASCII(str string) bool {
+	for idx := 0; idx < len(str); idx++ {
+		if str[idx] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// decomposeRune appends the full canonical decomposition of r.
+func decomposeRune(dst []rune, r rune) []rune {
+	if s := r - hangulSBase; s >= 0 && s < hangulSCount {
+		dst = append(dst, hangulLBase+s/hangulNCount, hangulVBase+(s%hangulNCount)/hangulTCount)
+		if t := s % hangulTCount; t != 0 {
+			dst = append(dst, hangulTBase+t)
+		}
+		return dst
+	}
+	if decomposition, ok := canonicalDecompositions[r]; ok {
+		for _, c := range decomposition {
+			dst = append(dst, c)
+		}
+		return dst
+	}
+	return append(dst, r)
+}
+
+// canonicalOrder stably sorts each run of non-starters by combining class.
+func canonicalOrder(runes []rune) []rune {
+	for idx := 1; idx < len(runes); idx++ {
+		class := combiningClasses[runes[idx]]
+		if class == 0 {
+			continue
+		}
+		for pos := idx; pos > 0 && combiningClasses[runes[pos-1]] > class; pos-- {
+			runes[pos], runes[pos-1] = runes[pos-1], runes[pos]
+		}
+	}
+	return runes
+}
+
+// canonicalCompose combines decomposed, canonically ordered runes into primary
+// composites wherever the combining mark isn't blocked from its starter.
+func canonicalCompose(runes []rune) []rune {
+	out := runes[:0]
+	starter := -1
+	lastClass := -1 // -1 means nothing since the starter
+	for _, r := range runes {
+		class := int(combiningClasses[r])
+		if starter >= 0 && (lastClass == -1 || (lastClass != 0 && lastClass < class)) {
+			if composite, ok := composePair(out[starter], r); ok {
+				out[starter] = composite
+				continue
+			}
+		}
+		if class == 0 {
+			starter, lastClass = len(out), -1
+		} else {
+			lastClass = class
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+func composePair(first, second rune) (rune, bool) {
+	if l := first - hangulLBase; l >= 0 && l < hangulLCount {
+		if v := second - hangulVBase; v >= 0 && v < hangulVCount {
+			return hangulSBase + (l*hangulVCount+v)*hangulTCount, true
+		}
+	}
+	if s := first - hangulSBase; s >= 0 && s < hangulSCount && s%hangulTCount == 0 {
+		if t := second - hangulTBase; t > 0 && t < hangulTCount {
+			return first + t, true
+		}
+	}
+	composite, ok := canonicalCompositions[[2]rune{first, second}]
+	return composite, ok
+}
@@ -0,0 +1,74 @@
+package apathy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalization(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name, input, nfc, nfd string
+	}{
+		{"ascii", "plain/file.txt", "plain/file.txt", "plain/file.txt"},
+		{"precomposed", "caf\u00e9", "caf\u00e9", "cafe\u0301"},
+		{"decomposed", "cafe\u0301", "caf\u00e9", "cafe\u0301"},
+		{"recursive", "\u01fa", "\u01fa", "A\u030a\u0301"},
+		// Marks are put in canonical order: dot below (220) before acute (230).
+		{"reordered", "e\u0301\u0323", "\u1eb9\u0301", "e\u0323\u0301"},
+		// Excluded from composition, so it stays decomposed.
+		{"exclusion", "\u0958", "\u0915\u093c", "\u0915\u093c"},
+		// Singletons decompose and never recompose.
+		{"singleton", "\u212b", "\u00c5", "A\u030a"},
+		// Hangul is done algorithmically.
+		{"hangul LV", "\uac00", "\uac00", "\u1100\u1161"},
+		{"hangul LVT", "\uac01", "\uac01", "\u1100\u1161\u11a8"},
+		// Only the first of two identical marks can compose.
+		{"blocked", "a\u0301\u0301", "\u00e1\u0301", "a\u0301\u0301"},
+		// Kirat Rai, from Unicode 16, so the tables aren't stuck on an old version.
+		{"kirat rai", "\U00016d68", "\U00016d68", "\U00016d67\U00016d67"},
+		// Invalid UTF-8 passes through, and is a boundary.
+		{"invalid", "e\xff\u0301", "e\xff\u0301", "e\xff\u0301"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.nfc, toNFC(tc.input), "NFC")
+			assert.Equal(t, tc.nfd, toNFD(tc.input), "NFD")
+			assert.Equal(t, tc.nfc, toNFC(tc.nfd), "NFC of NFD")
+			assert.Equal(t, tc.nfd, toNFD(tc.nfc), "NFD of NFC")
+		})
+	}
+}
+
+func TestNewAPieceNormalized(t *testing.T) {
+	t.Parallel()
+
+	// The same name, from a Mac and from Windows.
+	fromMac := "Assets\\Cafe\u0301/Cre\u0300me.png"
+	fromWindows := "Assets\\Caf\u00e9\\Cr\u00e8me.png"
+
+	assert.NotEqual(t, NewAPiece(fromMac), NewAPiece(fromWindows))
+	for _, form := range []UnicodeForm{UnicodeNFC, UnicodeNFD} {
+		mac, windows := NewAPieceNormalized(fromMac, form), NewAPieceNormalized(fromWindows, form)
+		assert.Equal(t, mac, windows, "%s", form)
+		assert.True(t, IsNormalized(mac, form), "%s", form)
+	}
+	assert.Equal(t, APiece("Assets/Caf\u00e9/Cr\u00e8me.png"), NewAPieceNormalized(fromMac, UnicodeNFC))
+	assert.Equal(t, APiece("Assets/Cafe\u0301/Cre\u0300me.png"), NewAPieceNormalized(fromWindows, UnicodeNFD))
+	assert.Equal(t, NewAPiece(fromMac), NewAPieceNormalized(fromMac, UnicodeNone))
+}
+
+func TestIsNormalized(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, IsNormalized(APiece("plain/ascii.txt"), UnicodeNFC))
+	assert.True(t, IsNormalized(APiece("plain/ascii.txt"), UnicodeNFD))
+	assert.True(t, IsNormalized(APiece("caf\u00e9"), UnicodeNFC))
+	assert.False(t, IsNormalized(APiece("caf\u00e9"), UnicodeNFD))
+	assert.False(t, IsNormalized(APiece("cafe\u0301"), UnicodeNFC))
+	assert.True(t, IsNormalized(APiece("cafe\u0301"), UnicodeNFD))
+	assert.True(t, IsNormalized(APiece("cafe\u0301"), UnicodeNone))
+	assert.Equal(t, APiece("x/caf\u00e9"), NormalizeUnicode(APiece("x/cafe\u0301"), UnicodeNFC))
+	assert.Equal(t, "NFD", UnicodeNFD.String())
+}
@@ -0,0 +1,222 @@
+package apathy
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ViolationKind classifies the ways a path can be unusable on a platform.
+type ViolationKind uint8
+
+const (
+	ViolationReservedName     ViolationKind = iota // A device name such as CON or COM1.
+	ViolationForbiddenChar                         // A character the platform won't accept in a name.
+	ViolationTrailingDotSpace                      // A name ending in '.' or ' ', which Windows silently strips.
+	ViolationComponentTooLong                      // A name longer than the platform allows.
+	ViolationPathTooLong                           // A whole path longer than the platform allows.
+	ViolationInvalidUTF8                           // A name that isn't valid UTF-8.
+	ViolationUnknownPlatform                       // A target Platform that Validate has no rules for.
+)
+
+func (k ViolationKind) String() string {
+	switch k {
+	case ViolationReservedName:
+		return "reserved name"
+	case ViolationForbiddenChar:
+		return "forbidden character"
+	case ViolationTrailingDotSpace:
+		return "trailing dot or space"
+	case ViolationComponentTooLong:
+		return "name too long"
+	case ViolationPathTooLong:
+		return "path too long"
+	case ViolationInvalidUTF8:
+		return "invalid UTF-8"
+	case ViolationUnknownPlatform:
+		return "unknown platform"
+	default:
+		return "unknown violation"
+	}
+}
+
+// Violation describes one problem Validate found with a path.
+type Violation struct {
+	Kind ViolationKind
+	// Index is the position of the offending component in the '/'-separated
+	// path, or -1 if the problem is with the path as a whole.
+	Index int
+	// Component is the offending component, or the whole path.
+	Component string
+	// Detail elaborates, e.g. which character was forbidden.
+	Detail string
+}
+
+func (v Violation) Error() string {
+	msg := fmt.Sprintf("%s: %q", v.Kind, v.Component)
+	if v.Detail != "" {
+		msg += " (" + v.Detail + ")"
+	}
+	return msg
+}
+
+// Unwrap lets errors.Is match a ViolationUnknownPlatform to ErrUnknownPlatform.
+func (v Violation) Unwrap() error {
+	if v.Kind == ViolationUnknownPlatform {
+		return ErrUnknownPlatform
+	}
+	return nil
+}
+
+// platformRules captures what Validate checks for each Platform. Lengths are in
+// bytes, or UTF-16 code units if utf16 is set.
+type platformRules struct {
+	forbidden     string
+	controlChars  bool
+	reservedNames bool
+	trailingDots  bool
+	requireUTF8   bool
+	utf16         bool
+	maxComponent  int
+	maxPath       int
+}
+
+var rulesFor = map[Platform]platformRules{
+	PlatformLinux: {
+		forbidden: "\x00", maxComponent: 255, maxPath: 4095,
+	},
+	PlatformDarwin: {
+		forbidden: "\x00:", requireUTF8: true, maxComponent: 255, maxPath: 1023,
+	},
+	PlatformWindows: {
+		forbidden: `<>:"|?*`, controlChars: true, reservedNames: true, trailingDots: true,
+		requireUTF8: true, utf16: true, maxComponent: 255, maxPath: 259, // MAX_PATH less the NUL
+	},
+}
+
+// rules returns what Validate checks for the platform, or an error wrapping
+// ErrUnknownPlatform if there's no such platform.
+func (p Platform) rules() (platformRules, error) {
+	rules, ok := rulesFor[p]
+	if !ok {
+		return platformRules{}, fmt.Errorf("%w: %s", ErrUnknownPlatform, p)
+	}
+	return rules, nil
+}
+
+// windowsReservedNames are device names that Windows reserves in every directory,
+// with or without an extension.
+var windowsReservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true, "CONIN$": true, "CONOUT$": true,
+	"COM0": true, "COM1": true, "COM2": true, "COM3": true, "COM4": true,
+	"COM5": true, "COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"COM¹": true, "COM²": true, "COM³": true,
+	"LPT0": true, "LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true,
+	"LPT5": true, "LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+	"LPT¹": true, "LPT²": true, "LPT³": true,
+}
+
+// isWindowsReservedName checks the part of the name before any extension, which
+// Windows compares case-insensitively and after dropping trailing spaces.
+func isWindowsReservedName(name string) bool {
+	if dot := strings.IndexByte(name, '.'); dot >= 0 {
+		name = name[:dot]
+	}
+	return windowsReservedNames[strings.ToUpper(strings.TrimRight(name, " "))]
+}
+
+// Validate checks a path against the target platform's rules for names and
+// lengths, and returns every violation it finds, in order. A nil result means
+// the path is fine. A leading UNC share, or drive letter on Windows, is not
+// subject to the rules for names. An unknown target is reported as a single
+// ViolationUnknownPlatform.
+func Validate(piece Piecer, target Platform) []Violation {
+	str := piece.Piece().String()
+	rules, err := target.rules()
+	if err != nil {
+		return []Violation{{Kind: ViolationUnknownPlatform, Index: -1, Component: str, Detail: target.String()}}
+	}
+
+	var violations []Violation
+	add := func(kind ViolationKind, idx int, component, detail string) {
+		violations = append(violations, Violation{Kind: kind, Index: idx, Component: component, Detail: detail})
+	}
+
+	// Skip over the volume, which isn't made of names.
+	volume := volumeLen(APiece(str), target)
+	components := strings.Split(str, "/")
+	offset := 0
+	for idx, component := range components {
+		start := offset
+		offset += len(component) + 1
+		if start < volume || component == "" || component == "." || component == ".." {
+			continue
+		}
+
+		validUTF8 := utf8.ValidString(component)
+		if rules.requireUTF8 && !validUTF8 {
+			add(ViolationInvalidUTF8, idx, component, "")
+		}
+		if forbidden := forbiddenChars(component, rules); forbidden != "" {
+			add(ViolationForbiddenChar, idx, component, forbidden)
+		}
+		if rules.reservedNames && isWindowsReservedName(component) {
+			add(ViolationReservedName, idx, component, "")
+		}
+		if rules.trailingDots && strings.TrimRight(component, ". ") != component {
+			add(ViolationTrailingDotSpace, idx, component, "")
+		}
+		if length := rules.length(component); length > rules.maxComponent {
+			add(ViolationComponentTooLong, idx, component, fmt.Sprintf("%d > %d", length, rules.maxComponent))
+		}
+	}
+	if length := rules.length(str); length > rules.maxPath {
+		add(ViolationPathTooLong, -1, str, fmt.Sprintf("%d > %d", length, rules.maxPath))
+	}
+	return violations
+}
+
+// volumeLen returns the length of the UNC share or, for Windows, the drive letter
+// at the start of the piece.
+func volumeLen(p APiece, target Platform) int {
+	if share := uncShareRoot(p); share != "" {
+		return share.Len()
+	}
+	if target == PlatformWindows && hasDriveLetter(p) {
+		return WindowsDriveLen
+	}
+	return 0
+}
+
+// forbiddenChars lists the distinct forbidden characters in a name, quoted.
+func forbiddenChars(name string, rules platformRules) string {
+	var found []string
+	seen := map[byte]bool{}
+	for idx := 0; idx < len(name); idx++ {
+		c := name[idx]
+		if (rules.controlChars && c < 0x20) || strings.IndexByte(rules.forbidden, c) >= 0 {
+			if !seen[c] {
+				seen[c] = true
+				found = append(found, fmt.Sprintf("%q", c))
+			}
+		}
+	}
+	return strings.Join(found, " ")
+}
+
+// length measures in bytes or UTF-16 code units as the platform does; invalid
+// UTF-8 bytes count one unit each.
+func (r platformRules) length(str string) int {
+	if !r.utf16 {
+		return len(str)
+	}
+	units := 0
+	for _, c := range str {
+		if c >= 0x10000 {
+			units += 2 // surrogate pair
+		} else {
+			units++
+		}
+	}
+	return units
+}
@@ -0,0 +1,120 @@
+package apathy
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	type want struct {
+		kind  ViolationKind
+		index int
+	}
+	long := strings.Repeat("x", 256)
+	for _, tc := range []struct {
+		name     string
+		piece    APiece
+		platform Platform
+		expected []want
+	}{
+		{"fine everywhere linux", "assets/textures/hero.png", PlatformLinux, nil},
+		{"fine everywhere windows", "C:/assets/textures/hero.png", PlatformWindows, nil},
+		{"fine everywhere darwin", "/assets/textures/hero.png", PlatformDarwin, nil},
+		{"unc root is not a name", "//server/share/x", PlatformWindows, nil},
+		{"dotdot is fine", "../x", PlatformWindows, nil},
+		{"reserved", "assets/CON", PlatformWindows, []want{{ViolationReservedName, 1}}},
+		{"reserved with ext", "aux.txt", PlatformWindows, []want{{ViolationReservedName, 0}}},
+		{"reserved lower and spaced", "a/nul .tar.gz", PlatformWindows, []want{{ViolationReservedName, 1}}},
+		{"reserved superscript", "com¹", PlatformWindows, []want{{ViolationReservedName, 0}}},
+		{"not reserved", "a/console/COM10/lpt", PlatformWindows, nil},
+		{"reserved fine on linux", "CON", PlatformLinux, nil},
+		{"forbidden", "a/what?.txt", PlatformWindows, []want{{ViolationForbiddenChar, 1}}},
+		{"forbidden control", "a\tb", PlatformWindows, []want{{ViolationForbiddenChar, 0}}},
+		{"colon in name", "c:/a/b:c", PlatformWindows, []want{{ViolationForbiddenChar, 2}}},
+		{"colon on darwin", "/a/b:c", PlatformDarwin, []want{{ViolationForbiddenChar, 2}}},
+		{"colon on linux", "/a/b:c", PlatformLinux, nil},
+		{"no drives on darwin", "c:/x", PlatformDarwin, []want{{ViolationForbiddenChar, 0}}},
+		{"trailing dot", "a./b", PlatformWindows, []want{{ViolationTrailingDotSpace, 0}}},
+		{"trailing space", "a/b ", PlatformWindows, []want{{ViolationTrailingDotSpace, 1}}},
+		{"invalid utf8 windows", "a/\xff", PlatformWindows, []want{{ViolationInvalidUTF8, 1}}},
+		{"invalid utf8 darwin", "a/\xff", PlatformDarwin, []want{{ViolationInvalidUTF8, 1}}},
+		{"invalid utf8 linux", "a/\xff", PlatformLinux, nil},
+		{"long component", APiece("a/" + long), PlatformLinux, []want{{ViolationComponentTooLong, 1}}},
+		{"long path windows", APiece(strings.Repeat("abcdefghi/", 26)), PlatformWindows, []want{{ViolationPathTooLong, -1}}},
+		{"long path linux", APiece(strings.Repeat("abcdefghi/", 26)), PlatformLinux, nil},
+		{"several", "CON./x|y", PlatformWindows, []want{
+			{ViolationReservedName, 0}, {ViolationTrailingDotSpace, 0}, {ViolationForbiddenChar, 1},
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []want
+			for _, v := range Validate(tc.piece, tc.platform) {
+				got = append(got, want{v.Kind, v.Index})
+			}
+			assert.ElementsMatch(t, tc.expected, got)
+		})
+	}
+}
+
+func TestValidate_Lengths(t *testing.T) {
+	t.Parallel()
+
+	// Windows counts UTF-16 units: 'é' is one, an emoji is two, but both are multiple bytes.
+	accents := APiece(strings.Repeat("é", 255))
+	assert.Empty(t, Validate(accents, PlatformWindows))
+	assert.NotEmpty(t, Validate(accents, PlatformLinux))
+	emoji := APiece(strings.Repeat("😀", 128))
+	assert.Equal(t, ViolationComponentTooLong, Validate(emoji, PlatformWindows)[0].Kind)
+}
+
+func TestValidate_UnknownPlatform(t *testing.T) {
+	t.Parallel()
+
+	// Without rules, every length limit would be zero; say what's wrong instead.
+	violations := Validate(APiece("a/b"), Platform(99))
+	if assert.Len(t, violations, 1) {
+		assert.Equal(t, ViolationUnknownPlatform, violations[0].Kind)
+		assert.ErrorIs(t, violations[0], ErrUnknownPlatform)
+		assert.Equal(t, `unknown platform: "a/b" (Platform(99))`, violations[0].Error())
+	}
+	assert.NotErrorIs(t, Violation{Kind: ViolationReservedName}, ErrUnknownPlatform)
+	assert.Equal(t, APiece("a:b"), Sanitize(APiece("a:b"), Platform(99)))
+}
+
+func TestViolation_Error(t *testing.T) {
+	t.Parallel()
+
+	violations := Validate(APiece("dir/a<b>"), PlatformWindows)
+	if assert.Len(t, violations, 1) {
+		assert.Equal(t, `forbidden character: "a<b>" ('<' '>')`, violations[0].Error())
+	}
+	assert.Equal(t, `reserved name: "CON"`, Violation{Kind: ViolationReservedName, Component: "CON"}.Error())
+	assert.Equal(t, "unknown violation", ViolationKind(99).String())
+}
+
+func TestPlatform(t *testing.T) {
+	t.Parallel()
+
+	for _, platform := range []Platform{PlatformLinux, PlatformDarwin, PlatformWindows} {
+		parsed, err := ParsePlatform(platform.String())
+		assert.NoError(t, err)
+		assert.Equal(t, platform, parsed)
+	}
+	parsed, err := ParsePlatform("macOS")
+	assert.NoError(t, err)
+	assert.Equal(t, PlatformDarwin, parsed)
+	_, err = ParsePlatform("beos")
+	assert.ErrorIs(t, err, ErrUnknownPlatform)
+	_, err = ParsePlatform(Platform(99).String())
+	assert.ErrorIs(t, err, ErrUnknownPlatform)
+
+	assert.Equal(t, FlavourWindows, PlatformWindows.Flavour())
+	assert.Equal(t, FlavourPosix, PlatformDarwin.Flavour())
+	assert.Equal(t, PlatformDarwin, platformForGOOS("darwin"))
+	assert.Equal(t, PlatformLinux, platformForGOOS("freebsd"))
+	assert.Equal(t, NativeFlavour, NativePlatform.Flavour())
+}
@@ -0,0 +1,74 @@
+package apathy
+
+import (
+	"errors"
+	"io/fs"
+)
+
+// WalkFunc is called by Walk for each APath it visits. If err is non-nil, p is a
+// directory that couldn't be read, and this is the second call for it.
+//
+// Returning fs.SkipDir from the first call for a directory skips its contents,
+// and from a non-directory skips the rest of its directory; returning fs.SkipAll
+// stops the walk without error. Any other error stops the walk and is returned.
+type WalkFunc func(p APath, err error) error
+
+// Walk visits root and, if it's a directory, everything beneath it in lexical
+// order, in the manner of fs.WalkDir. Each APath is built from its DirEntry's
+// Info, which on unix is an Lstat of the item as it's listed (Windows gets it
+// from the listing itself), so every item costs a system call. Symlinks are
+// reported but not followed. Items that vanish or can't be Lstat()d during the
+// walk are reported as not existing or AInaccessible, respectively.
+func Walk(root APath, fn WalkFunc) error {
+	err := walk(root, fn)
+	if errors.Is(err, fs.SkipDir) || errors.Is(err, fs.SkipAll) {
+		return nil
+	}
+	return err
+}
+
+func walk(p APath, fn WalkFunc) error {
+	if err := fn(p, nil); err != nil || !p.IsDir() {
+		if errors.Is(err, fs.SkipDir) && p.IsDir() {
+			return nil
+		}
+		return err
+	}
+	children, err := readDirAPaths(p.Piece())
+	if err != nil {
+		if err = fn(p, err); err != nil {
+			if errors.Is(err, fs.SkipDir) {
+				return nil
+			}
+			return err
+		}
+	}
+	for _, child := range children {
+		if err := walk(child, fn); err != nil {
+			if errors.Is(err, fs.SkipDir) {
+				break
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// readDirAPaths lists a directory as APaths, in lexical order, using each
+// DirEntry's Info.
+func readDirAPaths(dir APiece) ([]APath, error) {
+	entries, err := ReadDir(Normalize(dir))
+	if err != nil {
+		return nil, &PathError{Op: "ReadDir", Piece: dir, Err: err}
+	}
+	children := make([]APath, 0, len(entries))
+	for _, entry := range entries {
+		info, infoErr := entry.Info()
+		child, err := newAPathTolerant("ReadDir", Join(dir, APiece(entry.Name())), info, infoErr)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+	}
+	return children, nil
+}
@@ -0,0 +1,113 @@
+package apathy
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// makeTree creates the files, or directories for names ending in '/', under dir.
+func makeTree(t *testing.T, dir string, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if name[len(name)-1] == '/' {
+			require.NoError(t, os.MkdirAll(path, 0755))
+		} else {
+			require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+			require.NoError(t, os.WriteFile(path, []byte(name), 0644))
+		}
+	}
+}
+
+func TestWalk(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	makeTree(t, dir, "b/", "a/2.txt", "a/1.txt", "c.txt", "a/skip/deep.txt")
+	root := MustNewAPath(NewAPiece(dir))
+
+	var visited []APiece
+	err := Walk(root, func(p APath, err error) error {
+		require.NoError(t, err)
+		rel, _ := trimBase(root.Piece(), p.Piece())
+		visited = append(visited, rel)
+		if rel == "a/skip" {
+			return fs.SkipDir
+		}
+		if rel == "c.txt" {
+			assert.True(t, p.IsFile())
+			assert.EqualValues(t, len("c.txt"), p.Size())
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []APiece{"", "a", "a/1.txt", "a/2.txt", "a/skip", "b", "c.txt"}, visited)
+
+	// SkipDir from a file skips the rest of its directory.
+	visited = nil
+	require.NoError(t, Walk(root, func(p APath, err error) error {
+		rel, _ := trimBase(root.Piece(), p.Piece())
+		visited = append(visited, rel)
+		if rel == "a/1.txt" {
+			return fs.SkipDir
+		}
+		return nil
+	}))
+	assert.Equal(t, []APiece{"", "a", "a/1.txt", "b", "c.txt"}, visited)
+
+	// SkipAll stops quietly; other errors are returned.
+	count := 0
+	require.NoError(t, Walk(root, func(p APath, err error) error {
+		count++
+		return fs.SkipAll
+	}))
+	assert.Equal(t, 1, count)
+	stop := errors.New("stop")
+	assert.Equal(t, stop, Walk(root, func(p APath, err error) error { return stop }))
+
+	// A file root is visited on its own.
+	visited = nil
+	require.NoError(t, Walk(MustNewAPath(NewAPiece(dir), "c.txt"), func(p APath, err error) error {
+		visited = append(visited, Base(p))
+		return nil
+	}))
+	assert.Equal(t, []APiece{"c.txt"}, visited)
+}
+
+func TestWalk_Unreadable(t *testing.T) {
+	// Can't be parallel because it modifies globals.
+	dir := t.TempDir()
+	makeTree(t, dir, "locked/secret.txt", "open.txt")
+	root := MustNewAPath(NewAPiece(dir))
+
+	denied := errors.New("denied")
+	realReadDir := ReadDir
+	defer withSaved(&ReadDir, func(name string) ([]os.DirEntry, error) {
+		if filepath.Base(name) == "locked" {
+			return nil, denied
+		}
+		return realReadDir(name)
+	})()
+
+	var errs []error
+	var visited []APiece
+	require.NoError(t, Walk(root, func(p APath, err error) error {
+		visited = append(visited, Base(p))
+		if err != nil {
+			errs = append(errs, err)
+		}
+		return nil
+	}))
+	assert.Equal(t, []APiece{Base(root), "locked", "locked", "open.txt"}, visited)
+	require.Len(t, errs, 1)
+	assert.ErrorIs(t, errs[0], denied)
+	var pathErr *PathError
+	require.ErrorAs(t, errs[0], &pathErr)
+	assert.Equal(t, Join(root.Piece(), "locked"), pathErr.Piece)
+}